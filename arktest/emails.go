@@ -0,0 +1,462 @@
+package arktest
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/mail"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Email is an email accepted by a [Server].
+type Email struct {
+	ID      string
+	From    string
+	To      []string
+	Cc      []string
+	Bcc     []string
+	ReplyTo string
+	Subject string
+	HTML    string
+	Text    string
+	Tag     string
+	Headers map[string]string
+	// Metadata is the metadata of the email, or of the email of a batch.
+	Metadata    map[string]string
+	Attachments []Attachment
+	// RawMessage is the decoded MIME message of emails sent with
+	// [ark.EmailService.SendRaw], whose Subject is read from it.
+	RawMessage []byte
+	// Status is "sent", unless changed with [Server.SetEmailStatus].
+	Status string
+	// TenantID is the ID of the tenant whose API credential sent the email, or
+	// empty if it was sent with the API key of the account.
+	TenantID string
+	// Batch reports whether the email was sent in a batch.
+	Batch  bool
+	SentAt time.Time
+}
+
+// Attachment is an attachment of an [Email].
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+}
+
+// SentEmails returns the emails accepted so far, in the order they were
+// accepted.
+func (s *Server) SentEmails() []Email {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	emails := make([]Email, len(s.emails))
+	for i, e := range s.emails {
+		emails[i] = e.clone()
+	}
+	return emails
+}
+
+// SetEmailStatus changes the status of the email with the given ID, e.g. to
+// "hardfail" to test what happens when it could not be delivered. Failed emails
+// can be retried, which sets their status back to "pending".
+func (s *Server) SetEmailStatus(id, status string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e := s.findEmail(id, nil)
+	if e == nil {
+		return fmt.Errorf("arktest: no email with ID %q", id)
+	}
+	e.Status = status
+	return nil
+}
+
+func (e *Email) clone() Email {
+	c := *e
+	c.To = slices.Clone(e.To)
+	c.Cc = slices.Clone(e.Cc)
+	c.Bcc = slices.Clone(e.Bcc)
+	c.Headers = maps.Clone(e.Headers)
+	c.Metadata = maps.Clone(e.Metadata)
+	c.Attachments = slices.Clone(e.Attachments)
+	c.RawMessage = bytes.Clone(e.RawMessage)
+	return c
+}
+
+// findEmail returns the email with the given ID, if it is visible to tenant.
+func (s *Server) findEmail(id string, tenant *tenant) *Email {
+	for _, e := range s.emails {
+		if e.ID == id && (tenant == nil || e.TenantID == tenant.ID) {
+			return e
+		}
+	}
+	return nil
+}
+
+// accept stores a new email, or responds with an error if the send limit was
+// reached.
+func (s *Server) accept(w *response, e *Email, tenant *tenant) bool {
+	if s.sentSince(startOfDay(s.now())) >= s.sendLimit {
+		w.error(http.StatusTooManyRequests, "send_limit_exceeded", "Daily send limit exceeded", nil)
+		return false
+	}
+	e.ID = fmt.Sprintf("msg_%d", s.nextID())
+	e.Status = "sent"
+	e.SentAt = s.now()
+	if tenant != nil {
+		e.TenantID = tenant.ID
+	}
+	s.emails = append(s.emails, e)
+	return true
+}
+
+func (s *Server) sentSince(start time.Time) int64 {
+	var n int64
+	for _, e := range s.emails {
+		if !e.SentAt.Before(start) {
+			n++
+		}
+	}
+	return n
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+type sendEmailBody struct {
+	From        string            `json:"from"`
+	Subject     string            `json:"subject"`
+	To          []string          `json:"to"`
+	Cc          []string          `json:"cc"`
+	Bcc         []string          `json:"bcc"`
+	ReplyTo     string            `json:"replyTo"`
+	HTML        string            `json:"html"`
+	Text        string            `json:"text"`
+	Tag         string            `json:"tag"`
+	Headers     map[string]string `json:"headers"`
+	Metadata    map[string]string `json:"metadata"`
+	Attachments []struct {
+		Content     string `json:"content"`
+		ContentType string `json:"contentType"`
+		Filename    string `json:"filename"`
+	} `json:"attachments"`
+}
+
+func (s *Server) sendEmail(w *response, r *http.Request, tenant *tenant) {
+	var body sendEmailBody
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	validateAddress(fields, "from", body.From)
+	validateRecipients(fields, "to", body.To, true)
+	validateRecipients(fields, "cc", body.Cc, false)
+	validateRecipients(fields, "bcc", body.Bcc, false)
+	if body.ReplyTo != "" {
+		validateAddress(fields, "replyTo", body.ReplyTo)
+	}
+	if body.Subject == "" {
+		fields["subject"] = "is required"
+	}
+	if body.HTML == "" && body.Text == "" {
+		fields["html"] = "html or text is required"
+	}
+	e := &Email{
+		From:     body.From,
+		To:       body.To,
+		Cc:       body.Cc,
+		Bcc:      body.Bcc,
+		ReplyTo:  body.ReplyTo,
+		Subject:  body.Subject,
+		HTML:     body.HTML,
+		Text:     body.Text,
+		Tag:      body.Tag,
+		Headers:  body.Headers,
+		Metadata: body.Metadata,
+	}
+	for i, a := range body.Attachments {
+		content, err := base64.StdEncoding.DecodeString(a.Content)
+		if err != nil {
+			fields[fmt.Sprintf("attachments.%d.content", i)] = "is not valid base64"
+		}
+		if a.Filename == "" {
+			fields[fmt.Sprintf("attachments.%d.filename", i)] = "is required"
+		}
+		e.Attachments = append(e.Attachments, Attachment{Filename: a.Filename, ContentType: a.ContentType, Content: content})
+	}
+	if w.invalid(fields) || !s.accept(w, e, tenant) {
+		return
+	}
+	w.json(http.StatusOK, sendResponse(e))
+}
+
+func (s *Server) sendBatch(w *response, r *http.Request, tenant *tenant) {
+	var body struct {
+		From   string `json:"from"`
+		Emails []struct {
+			To       []string          `json:"to"`
+			Subject  string            `json:"subject"`
+			HTML     string            `json:"html"`
+			Text     string            `json:"text"`
+			Tag      string            `json:"tag"`
+			Metadata map[string]string `json:"metadata"`
+		} `json:"emails"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	validateAddress(fields, "from", body.From)
+	if len(body.Emails) == 0 {
+		fields["emails"] = "is required"
+	} else if len(body.Emails) > 100 {
+		fields["emails"] = "must contain at most 100 emails"
+	}
+	if w.invalid(fields) {
+		return
+	}
+
+	messages := map[string]any{}
+	failed := 0
+	for _, be := range body.Emails {
+		fields := map[string]string{}
+		validateRecipients(fields, "to", be.To, true)
+		if be.Subject == "" || be.HTML == "" && be.Text == "" {
+			fields["subject"] = "subject and html or text are required"
+		}
+		if len(fields) > 0 {
+			failed++
+			continue
+		}
+		e := &Email{
+			From:     body.From,
+			To:       be.To,
+			Subject:  be.Subject,
+			HTML:     be.HTML,
+			Text:     be.Text,
+			Tag:      be.Tag,
+			Metadata: be.Metadata,
+			Batch:    true,
+		}
+		if !s.accept(w, e, tenant) {
+			return
+		}
+		for _, to := range e.To {
+			messages[to] = map[string]any{"id": e.ID}
+		}
+	}
+	w.json(http.StatusOK, map[string]any{
+		"total":    len(body.Emails),
+		"accepted": len(body.Emails) - failed,
+		"failed":   failed,
+		"messages": messages,
+	})
+}
+
+func (s *Server) sendRaw(w *response, r *http.Request, tenant *tenant) {
+	var body struct {
+		From       string   `json:"from"`
+		To         []string `json:"to"`
+		RawMessage string   `json:"rawMessage"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	validateAddress(fields, "from", body.From)
+	validateRecipients(fields, "to", body.To, true)
+	raw, err := base64.StdEncoding.DecodeString(body.RawMessage)
+	if err != nil || len(raw) == 0 {
+		fields["rawMessage"] = "must be a base64-encoded MIME message"
+	}
+	if w.invalid(fields) {
+		return
+	}
+	e := &Email{From: body.From, To: body.To, RawMessage: raw}
+	if msg, err := mail.ReadMessage(bytes.NewReader(raw)); err == nil {
+		e.Subject = msg.Header.Get("Subject")
+	}
+	if !s.accept(w, e, tenant) {
+		return
+	}
+	w.json(http.StatusOK, sendResponse(e))
+}
+
+func sendResponse(e *Email) map[string]any {
+	return map[string]any{
+		"id":        e.ID,
+		"status":    e.Status,
+		"to":        e.To,
+		"messageId": messageID(e),
+	}
+}
+
+func messageID(e *Email) string {
+	return "<" + e.ID + "@arktest>"
+}
+
+func validateAddress(fields map[string]string, field, address string) {
+	if address == "" {
+		fields[field] = "is required"
+	} else if _, err := mail.ParseAddress(address); err != nil {
+		fields[field] = "is not a valid email address"
+	}
+}
+
+func validateRecipients(fields map[string]string, field string, addresses []string, required bool) {
+	if required && len(addresses) == 0 {
+		fields[field] = "is required"
+	}
+	if len(addresses) > 50 {
+		fields[field] = "must contain at most 50 addresses"
+	}
+	for i, address := range addresses {
+		validateAddress(fields, fmt.Sprintf("%s.%d", field, i), address)
+	}
+}
+
+func (s *Server) listEmails(w *response, r *http.Request, tenant *tenant) {
+	query := r.URL.Query()
+	var items []map[string]any
+	for i := len(s.emails) - 1; i >= 0; i-- {
+		e := s.emails[i]
+		switch {
+		case tenant != nil && e.TenantID != tenant.ID,
+			query.Has("status") && e.Status != query.Get("status"),
+			query.Has("tag") && e.Tag != query.Get("tag"),
+			query.Has("from") && !strings.EqualFold(addressOf(e.From), query.Get("from")),
+			query.Has("to") && !slices.ContainsFunc(e.To, func(to string) bool {
+				return strings.EqualFold(addressOf(to), query.Get("to"))
+			}):
+			continue
+		}
+		items = append(items, map[string]any{
+			"id":           e.ID,
+			"from":         e.From,
+			"to":           e.To[0],
+			"subject":      e.Subject,
+			"status":       e.Status,
+			"tag":          e.Tag,
+			"timestamp":    unixSeconds(e.SentAt),
+			"timestampIso": e.SentAt,
+		})
+	}
+	page(w, r, items)
+}
+
+func addressOf(s string) string {
+	if addr, err := mail.ParseAddress(s); err == nil {
+		return addr.Address
+	}
+	return s
+}
+
+func (s *Server) getEmail(w *response, r *http.Request, tenant *tenant) {
+	e := s.findEmail(r.PathValue("emailId"), tenant)
+	if e == nil {
+		w.notFound("Email")
+		return
+	}
+	attachments := []map[string]any{}
+	for _, a := range e.Attachments {
+		attachments = append(attachments, map[string]any{
+			"filename":    a.Filename,
+			"contentType": a.ContentType,
+			"data":        base64.StdEncoding.EncodeToString(a.Content),
+			"hash":        fmt.Sprintf("%x", sha256.Sum256(a.Content)),
+			"size":        len(a.Content),
+		})
+	}
+	data := map[string]any{
+		"id":           e.ID,
+		"from":         e.From,
+		"to":           e.To[0],
+		"subject":      e.Subject,
+		"status":       e.Status,
+		"scope":        "outgoing",
+		"tag":          e.Tag,
+		"messageId":    messageID(e),
+		"timestamp":    unixSeconds(e.SentAt),
+		"timestampIso": e.SentAt,
+		"htmlBody":     e.HTML,
+		"plainBody":    e.Text,
+		"headers":      e.Headers,
+		"attachments":  attachments,
+		"deliveries":   deliveries(e),
+		"activity":     map[string]any{"opens": []any{}, "clicks": []any{}},
+		"spam":         false,
+		"spamScore":    0,
+	}
+	if e.RawMessage != nil {
+		data["rawMessage"] = base64.StdEncoding.EncodeToString(e.RawMessage)
+	}
+	w.json(http.StatusOK, data)
+}
+
+// deliveries returns the delivery attempts of an email, of which the fake makes
+// a single one for emails which were sent.
+func deliveries(e *Email) []map[string]any {
+	if e.Status != "sent" {
+		return []map[string]any{}
+	}
+	return []map[string]any{{
+		"id":           e.ID + "_1",
+		"status":       "Sent",
+		"code":         250,
+		"output":       "250 OK",
+		"details":      fmt.Sprintf("Message for %s accepted by arktest", addressOf(e.To[0])),
+		"sentWithSsl":  true,
+		"timestamp":    unixSeconds(e.SentAt),
+		"timestampIso": e.SentAt,
+	}}
+}
+
+func (s *Server) getDeliveries(w *response, r *http.Request, tenant *tenant) {
+	e := s.findEmail(r.PathValue("emailId"), tenant)
+	if e == nil {
+		w.notFound("Email")
+		return
+	}
+	w.json(http.StatusOK, map[string]any{
+		"id":               e.ID,
+		"messageId":        messageID(e),
+		"status":           e.Status,
+		"deliveries":       deliveries(e),
+		"canRetryManually": canRetry(e),
+		"retryState": map[string]any{
+			"attempt":           0,
+			"attemptsRemaining": 0,
+			"maxAttempts":       18,
+			"manual":            false,
+			"processing":        e.Status == "pending",
+		},
+	})
+}
+
+func canRetry(e *Email) bool {
+	return e.Status == "softfail" || e.Status == "hardfail" || e.Status == "held"
+}
+
+func (s *Server) retryEmail(w *response, r *http.Request, tenant *tenant) {
+	e := s.findEmail(r.PathValue("emailId"), tenant)
+	if e == nil {
+		w.notFound("Email")
+		return
+	}
+	if !canRetry(e) {
+		w.error(http.StatusUnprocessableEntity, "cannot_retry", fmt.Sprintf("Email with status %s cannot be retried", e.Status), nil)
+		return
+	}
+	e.Status = "pending"
+	w.json(http.StatusOK, map[string]any{"id": e.ID, "message": "Email queued for retry"})
+}
+
+func unixSeconds(t time.Time) float64 {
+	return float64(t.UnixNano()) / float64(time.Second)
+}
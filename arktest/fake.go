@@ -0,0 +1,77 @@
+package arktest
+
+import (
+	"net/http"
+	"net/http/httptest"
+
+	"github.com/ArkHQ-io/ark-go"
+)
+
+// Fakes are in-memory implementations of the service interfaces of the SDK,
+// for tests of code which depends on them rather than on a [ark.Client]. They
+// are the services of a client of a [Server] serving it in-process, without a
+// listener, so they validate requests and keep data like a Server does. There
+// are no fakes of [ark.LogsAPI] and [ark.PlatformWebhooksAPI], which the Server
+// does not implement.
+//
+//	fakes := arktest.NewFakes()
+//	notifier := NewNotifier(fakes.Emails) // accepts an ark.EmailsAPI
+//	// ... code under test sending emails with notifier
+//	emails := fakes.Server.SentEmails()
+type Fakes struct {
+	// Server serves the fakes, to inspect their data or inject faults. It need
+	// not be closed.
+	Server *Server
+
+	Emails             ark.EmailsAPI
+	Usage              ark.UsageAPI
+	Limits             ark.LimitsAPI
+	Tenants            ark.TenantsAPI
+	TenantCredentials  ark.TenantCredentialsAPI
+	TenantDomains      ark.TenantDomainsAPI
+	TenantSuppressions ark.TenantSuppressionsAPI
+	TenantTracking     ark.TenantTrackingAPI
+	TenantUsage        ark.TenantUsageAPI
+	TenantWebhooks     ark.TenantWebhooksAPI
+
+	client ark.Client
+}
+
+// NewFakes returns fakes backed by a new in-process Server, with no data.
+func NewFakes() *Fakes {
+	f := &Fakes{Server: newServer()}
+	f.Server.URL = "http://arktest.invalid"
+	f.client = f.Server.Client()
+	f.Emails = &f.client.Emails
+	f.Usage = &f.client.Usage
+	f.Limits = &f.client.Limits
+	f.Tenants = &f.client.Tenants
+	f.TenantCredentials = &f.client.Tenants.Credentials
+	f.TenantDomains = &f.client.Tenants.Domains
+	f.TenantSuppressions = &f.client.Tenants.Suppressions
+	f.TenantTracking = &f.client.Tenants.Tracking
+	f.TenantUsage = &f.client.Tenants.Usage
+	f.TenantWebhooks = &f.client.Tenants.Webhooks
+	return f
+}
+
+// inProcessTransport serves requests with a handler, without a network
+// connection.
+type inProcessTransport struct {
+	handler http.Handler
+}
+
+func (t inProcessTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := req.Clone(req.Context())
+	if r.Body == nil {
+		r.Body = http.NoBody
+	}
+	rec := httptest.NewRecorder()
+	t.handler.ServeHTTP(rec, r)
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	res := rec.Result()
+	res.Request = req
+	return res, nil
+}
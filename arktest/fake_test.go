@@ -0,0 +1,67 @@
+package arktest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/arktest"
+)
+
+// welcome is code under test, which depends on service interfaces rather than
+// on a client.
+func welcome(ctx context.Context, emails ark.EmailsAPI, tenants ark.TenantsAPI, tenantID string) (string, error) {
+	tenant, err := tenants.Get(ctx, tenantID)
+	if err != nil {
+		return "", err
+	}
+	res, err := emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@example.com",
+		To:      []string{"user@example.com"},
+		Subject: "Welcome to " + tenant.Data.Name,
+		Text:    ark.String("Hi!"),
+	})
+	if err != nil {
+		return "", err
+	}
+	return res.Data.ID, nil
+}
+
+func TestFakes(t *testing.T) {
+	ctx := context.Background()
+	fakes := arktest.NewFakes()
+	tenant, err := fakes.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	id, err := welcome(ctx, fakes.Emails, fakes.Tenants, tenant.Data.ID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	emails := fakes.Server.SentEmails()
+	if len(emails) != 1 || emails[0].ID != id || emails[0].Subject != "Welcome to Acme" {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+	email, err := fakes.Emails.Get(ctx, id, ark.EmailGetParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if email.Data.Subject != "Welcome to Acme" {
+		t.Fatalf("unexpected email %s", email.RawJSON())
+	}
+
+	_, err = welcome(ctx, fakes.Emails, fakes.Tenants, "unknown")
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	fakes.Server.Inject(arktest.Fault{Path: "/v1/emails", Status: http.StatusForbidden})
+	_, err = welcome(ctx, fakes.Emails, fakes.Tenants, tenant.Data.ID)
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusForbidden {
+		t.Fatalf("expected a 403 error, got %v", err)
+	}
+}
@@ -0,0 +1,370 @@
+package arktest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// RecorderMode selects whether a [Recorder] records or replays its cassette.
+type RecorderMode int
+
+const (
+	// ModeAuto replays the cassette if its file exists, and records it
+	// otherwise. Delete the file to record it again.
+	ModeAuto RecorderMode = iota
+	// ModeRecord sends requests to the API and records them, replacing the
+	// cassette.
+	ModeRecord
+	// ModeReplay replays the cassette, which must exist, without sending any
+	// request.
+	ModeReplay
+)
+
+// MatchOn is a set of the parts of requests which must be equal for a request
+// to be replayed with a recorded response.
+type MatchOn int
+
+const (
+	MatchMethod MatchOn = 1 << iota
+	MatchPath
+	// MatchQuery compares the query parameters, in any order.
+	MatchQuery
+	// MatchBody compares the bodies, as JSON values for JSON bodies so that the
+	// order of their keys does not matter.
+	MatchBody
+
+	MatchAll = MatchMethod | MatchPath | MatchQuery | MatchBody
+)
+
+// redacted replaces secrets in cassettes.
+const redacted = "[REDACTED]"
+
+// volatileHeaders are the request headers which are not recorded, because they
+// change with each attempt, SDK version or platform.
+var volatileHeaders = []string{
+	"User-Agent",
+	"X-Stainless-Retry-Count",
+	"X-Stainless-Timeout",
+	"X-Stainless-Lang",
+	"X-Stainless-Package-Version",
+	"X-Stainless-OS",
+	"X-Stainless-Arch",
+	"X-Stainless-Runtime",
+	"X-Stainless-Runtime-Version",
+}
+
+// secretHeaders are the headers whose values are replaced when recorded.
+var secretHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// RecorderOptions configures a [Recorder]. The zero value records the cassette
+// if it does not exist, and matches requests on all of MatchAll.
+type RecorderOptions struct {
+	Mode RecorderMode
+	// Match defaults to MatchAll.
+	Match MatchOn
+	// MatchHeaders are request headers which must also be equal, e.g.
+	// "Idempotency-Key".
+	MatchHeaders []string
+	// Scrub is called with each interaction before it is recorded, to remove
+	// secrets besides those scrubbed by default: the Authorization and cookie
+	// headers, and the keys of tenant credentials.
+	Scrub func(*Interaction)
+	// Transport sends the requests which are recorded. It defaults to
+	// [http.DefaultTransport].
+	Transport http.RoundTripper
+}
+
+// Interaction is a request and its response, recorded in a cassette.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is a request recorded in a cassette.
+type RecordedRequest struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// RecordedResponse is a response recorded in a cassette.
+type RecordedResponse struct {
+	StatusCode int         `json:"statusCode"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+type cassette struct {
+	Interactions []*Interaction `json:"interactions"`
+}
+
+// Recorder is an [http.RoundTripper] which records the requests made through
+// it, with their responses, in a cassette file, and replays them in later runs
+// without network access. Interactions are replayed in the order they were
+// recorded, and each of them once, so that retries and repeated requests replay
+// deterministically.
+//
+//	rec, err := arktest.NewRecorder("testdata/send.json", arktest.RecorderOptions{})
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer rec.Stop()
+//	client := ark.NewClient(rec.Option())
+type Recorder struct {
+	path      string
+	opts      RecorderOptions
+	recording bool
+
+	mu           sync.Mutex
+	interactions []*Interaction
+	used         []bool
+}
+
+// NewRecorder returns a Recorder of the cassette at path.
+func NewRecorder(path string, opts RecorderOptions) (*Recorder, error) {
+	if opts.Match == 0 {
+		opts.Match = MatchAll
+	}
+	if opts.Transport == nil {
+		opts.Transport = http.DefaultTransport
+	}
+	r := &Recorder{path: path, opts: opts, recording: opts.Mode == ModeRecord}
+	if r.recording {
+		return r, nil
+	}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) && opts.Mode == ModeAuto {
+		r.recording = true
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("arktest: reading cassette: %w", err)
+	}
+	var c cassette
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf("arktest: reading cassette %s: %w", path, err)
+	}
+	r.interactions = c.Interactions
+	r.used = make([]bool, len(c.Interactions))
+	return r, nil
+}
+
+// Recording reports whether the recorder records its cassette, rather than
+// replaying it.
+func (r *Recorder) Recording() bool {
+	return r.recording
+}
+
+// Option returns a RequestOption which sends requests through the recorder.
+func (r *Recorder) Option() option.RequestOption {
+	return option.WithHTTPClient(&http.Client{Transport: r})
+}
+
+// Stop writes the cassette, if it was recorded.
+func (r *Recorder) Stop() error {
+	if !r.recording {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	data, err := json.MarshalIndent(cassette{Interactions: r.interactions}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return fmt.Errorf("arktest: writing cassette: %w", err)
+	}
+	if err := os.WriteFile(r.path, append(data, '\n'), 0o644); err != nil {
+		return fmt.Errorf("arktest: writing cassette: %w", err)
+	}
+	return nil
+}
+
+// ErrNoInteraction is matched with [errors.Is] by the error returned when a
+// request being replayed matches no unused interaction of the cassette.
+var ErrNoInteraction = errors.New("arktest: no recorded interaction matches the request")
+
+type noInteractionError struct {
+	method, url string
+}
+
+func (e *noInteractionError) Error() string {
+	return fmt.Sprintf("%s: %s %s", ErrNoInteraction, e.method, e.url)
+}
+
+func (e *noInteractionError) Is(target error) bool { return target == ErrNoInteraction }
+
+// Retryable returns false, so that the SDK does not retry requests which can
+// never be replayed.
+func (e *noInteractionError) Retryable() bool { return false }
+
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+	if r.recording {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	out := req.Clone(req.Context())
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	res, err := r.opts.Transport.RoundTrip(out)
+	if err != nil {
+		return nil, err
+	}
+	resBody, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = io.NopCloser(bytes.NewReader(resBody))
+
+	in := &Interaction{
+		Request: RecordedRequest{
+			Method: req.Method,
+			URL:    req.URL.String(),
+			Header: req.Header.Clone(),
+			Body:   string(body),
+		},
+		Response: RecordedResponse{
+			StatusCode: res.StatusCode,
+			Header:     res.Header.Clone(),
+			Body:       string(resBody),
+		},
+	}
+	scrub(in)
+	if r.opts.Scrub != nil {
+		r.opts.Scrub(in)
+	}
+	r.mu.Lock()
+	r.interactions = append(r.interactions, in)
+	r.mu.Unlock()
+	return res, nil
+}
+
+// scrub removes volatile headers and secrets from an interaction.
+func scrub(in *Interaction) {
+	for _, key := range volatileHeaders {
+		in.Request.Header.Del(key)
+	}
+	for _, key := range secretHeaders {
+		for _, header := range []http.Header{in.Request.Header, in.Response.Header} {
+			if header.Get(key) != "" {
+				header.Set(key, redacted)
+			}
+		}
+	}
+	if u, err := url.Parse(in.Request.URL); err == nil && strings.Contains(u.Path, "/credentials") {
+		in.Response.Body = scrubCredentialKeys(in.Response.Body)
+	}
+}
+
+// scrubCredentialKeys replaces the keys of the credentials of a response body.
+func scrubCredentialKeys(body string) string {
+	var v map[string]any
+	if err := json.Unmarshal([]byte(body), &v); err != nil {
+		return body
+	}
+	var scrubbed bool
+	scrubKey := func(item any) {
+		if m, ok := item.(map[string]any); ok {
+			if key, ok := m["key"].(string); ok && key != "" {
+				m["key"] = redacted
+				scrubbed = true
+			}
+		}
+	}
+	switch data := v["data"].(type) {
+	case map[string]any:
+		scrubKey(data)
+	case []any:
+		for _, item := range data {
+			scrubKey(item)
+		}
+	}
+	if !scrubbed {
+		return body
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return body
+	}
+	return string(data)
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, in := range r.interactions {
+		if r.used[i] || !r.matches(in.Request, req, body) {
+			continue
+		}
+		r.used[i] = true
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", in.Response.StatusCode, http.StatusText(in.Response.StatusCode)),
+			StatusCode:    in.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        in.Response.Header.Clone(),
+			Body:          io.NopCloser(strings.NewReader(in.Response.Body)),
+			ContentLength: int64(len(in.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, &noInteractionError{method: req.Method, url: req.URL.String()}
+}
+
+func (r *Recorder) matches(recorded RecordedRequest, req *http.Request, body []byte) bool {
+	u, err := url.Parse(recorded.URL)
+	if err != nil {
+		return false
+	}
+	match := r.opts.Match
+	switch {
+	case match&MatchMethod != 0 && recorded.Method != req.Method,
+		match&MatchPath != 0 && u.Path != req.URL.Path,
+		match&MatchQuery != 0 && !reflect.DeepEqual(u.Query(), req.URL.Query()),
+		match&MatchBody != 0 && !bodiesEqual([]byte(recorded.Body), body):
+		return false
+	}
+	for _, key := range r.opts.MatchHeaders {
+		if recorded.Header.Get(key) != req.Header.Get(key) {
+			return false
+		}
+	}
+	return true
+}
+
+// bodiesEqual reports whether two bodies are equal, as JSON values if both are
+// JSON.
+func bodiesEqual(a, b []byte) bool {
+	var va, vb any
+	if json.Unmarshal(a, &va) == nil && json.Unmarshal(b, &vb) == nil {
+		return reflect.DeepEqual(va, vb)
+	}
+	return bytes.Equal(a, b)
+}
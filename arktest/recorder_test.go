@@ -0,0 +1,129 @@
+package arktest_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/arktest"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassettes", "tenant.json")
+	ctx := context.Background()
+
+	// Record against a fake server standing in for the API.
+	srv := arktest.NewServer()
+	rec, err := arktest.NewRecorder(path, arktest.RecorderOptions{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !rec.Recording() {
+		t.Fatalf("expected a missing cassette to be recorded")
+	}
+	client := srv.Client(rec.Option(), option.WithRetryPolicy(option.FixedBackoff{Delay: time.Millisecond}))
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	srv.Inject(arktest.Fault{Status: http.StatusServiceUnavailable, Times: 1})
+	credential, err := client.Tenants.Credentials.New(ctx, tenant.Data.ID, ark.TenantCredentialNewParams{Name: "app", Type: "api"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	srv.Close()
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	for _, secret := range []string{srv.APIKey, credential.Data.Key, "X-Stainless-Retry-Count", "X-Stainless-Os", "User-Agent"} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("expected %q not to be recorded:\n%s", secret, data)
+		}
+	}
+
+	// Replay without the server. The retried request replays its failed attempt
+	// first, and the body of the tenant matches whatever the order of its keys.
+	rec, err = arktest.NewRecorder(path, arktest.RecorderOptions{Mode: arktest.ModeReplay})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	client = ark.NewClient(rec.Option(), option.WithAPIKey("other"), option.WithRetryPolicy(option.FixedBackoff{Delay: time.Millisecond}))
+	replayed, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if replayed.Data.ID != tenant.Data.ID {
+		t.Fatalf("expected the recorded tenant %s, got %s", tenant.Data.ID, replayed.Data.ID)
+	}
+	_, err = client.Tenants.Credentials.New(ctx, tenant.Data.ID, ark.TenantCredentialNewParams{Name: "app", Type: "api"}, option.WithMaxRetries(0))
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the failed attempt to be replayed first, got %v", err)
+	}
+	replayedCredential, err := client.Tenants.Credentials.New(ctx, tenant.Data.ID, ark.TenantCredentialNewParams{Name: "app", Type: "api"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if replayedCredential.Data.ID != credential.Data.ID || replayedCredential.Data.Key != "[REDACTED]" {
+		t.Fatalf("unexpected credential %s", replayedCredential.RawJSON())
+	}
+
+	// Every interaction was replayed once.
+	_, err = client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if !errors.Is(err, arktest.ErrNoInteraction) {
+		t.Fatalf("expected ErrNoInteraction, got %v", err)
+	}
+}
+
+func TestRecorderMatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "list.json")
+	ctx := context.Background()
+
+	srv := arktest.NewServer()
+	defer srv.Close()
+	rec, err := arktest.NewRecorder(path, arktest.RecorderOptions{Mode: arktest.ModeRecord})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	client := srv.Client(rec.Option())
+	if _, err := client.Emails.List(ctx, ark.EmailListParams{Tag: ark.String("a"), PerPage: ark.Int(10)}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if err := rec.Stop(); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	for name, test := range map[string]struct {
+		match arktest.MatchOn
+		tag   string
+		ok    bool
+	}{
+		"same query":           {arktest.MatchAll, "a", true},
+		"other query":          {arktest.MatchAll, "b", false},
+		"other query, ignored": {arktest.MatchMethod | arktest.MatchPath, "b", true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			rec, err := arktest.NewRecorder(path, arktest.RecorderOptions{Mode: arktest.ModeReplay, Match: test.match})
+			if err != nil {
+				t.Fatalf("err should be nil: %s", err.Error())
+			}
+			client := ark.NewClient(rec.Option(), option.WithAPIKey("key"))
+			_, err = client.Emails.List(ctx, ark.EmailListParams{PerPage: ark.Int(10), Tag: ark.String(test.tag)})
+			if (err == nil) != test.ok {
+				t.Fatalf("expected the request to match: %v, got %v", test.ok, err)
+			}
+		})
+	}
+}
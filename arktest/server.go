@@ -0,0 +1,492 @@
+// Package arktest provides an in-process fake of the Ark API, to write unit
+// tests of code using the SDK which run without network access or a mock server.
+//
+// A [Server] keeps emails, tenants and their domains, suppressions, tracking
+// domains, webhooks and credentials in memory, so that what a test creates can be
+// read back, and reports usage and limits from them. Faults such as rate limits,
+// server errors and latency can be injected with [Server.Inject].
+//
+//	srv := arktest.NewServer()
+//	defer srv.Close()
+//	client := srv.Client()
+//	// ... code under test sending emails with client
+//	if emails := srv.SentEmails(); len(emails) != 1 {
+//		t.Fatalf("expected an email, got %d", len(emails))
+//	}
+//
+// The fake does not deliver emails, resolve DNS or call webhooks: emails are
+// sent as soon as they are accepted, and domains and tracking domains are
+// verified as soon as they are asked to be. Endpoints it does not implement,
+// like logs and platform webhooks, respond with 501 Not Implemented, which the
+// SDK does not retry.
+//
+// [NewFakes] serves the same fake in-process, as implementations of the service
+// interfaces of the SDK, like [ark.EmailsAPI], for code which depends on them,
+// except those of the endpoints it does not implement.
+//
+// A [Recorder] records the requests made to the real API into cassette files,
+// with their secrets scrubbed, and replays them in later runs, for tests which
+// the fake cannot serve.
+package arktest
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// DefaultAPIKey is the API key accepted by a new [Server].
+const DefaultAPIKey = "ark_test_key"
+
+// Server is a fake Ark API served over HTTP by an [httptest.Server]. Its API is
+// rooted at URL + "/v1/". It is safe for concurrent use.
+type Server struct {
+	// URL is the base URL of the server, of the form http://ipaddr:port with no
+	// trailing slash. The server of [NewFakes] does not listen, and its URL only
+	// serves its Client.
+	URL string
+	// APIKey is the API key accepted by the server, besides the keys of the API
+	// credentials of tenants. It defaults to [DefaultAPIKey], and must not be
+	// changed once the server is used.
+	APIKey string
+
+	srv     *httptest.Server
+	handler http.Handler
+	now     func() time.Time
+
+	mu          sync.Mutex
+	requests    []Request
+	faults      []*Fault
+	idempotency map[string]idempotentResponse
+	seq         int64
+	emails      []*Email
+	tenants     []*tenant
+	// sendLimit is the number of emails which can be sent per day.
+	sendLimit int64
+}
+
+// NewServer starts and returns a new Server, with no data. The caller should
+// call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := newServer()
+	s.srv = httptest.NewServer(s.handler)
+	s.URL = s.srv.URL
+	return s
+}
+
+// newServer returns a Server which does not listen yet.
+func newServer() *Server {
+	s := &Server{
+		APIKey:      DefaultAPIKey,
+		now:         time.Now,
+		idempotency: map[string]idempotentResponse{},
+		sendLimit:   10000,
+	}
+	s.handler = s.routes()
+	return s
+}
+
+// Close shuts down the server and blocks until all outstanding requests on it
+// have completed.
+func (s *Server) Close() {
+	if s.srv != nil {
+		s.srv.Close()
+	}
+}
+
+// Client returns a client of the server, authenticated with its API key. The
+// given options are applied after those.
+func (s *Server) Client(opts ...option.RequestOption) ark.Client {
+	defaults := []option.RequestOption{
+		option.WithBaseURL(s.URL + "/v1/"),
+		option.WithAPIKey(s.APIKey),
+	}
+	if s.srv == nil {
+		defaults = append(defaults, option.WithHTTPClient(&http.Client{Transport: inProcessTransport{s.handler}}))
+	}
+	return ark.NewClient(append(defaults, opts...)...)
+}
+
+// Request is a request received by a [Server].
+type Request struct {
+	Method string
+	// Path is the path of the request URL, e.g. "/v1/emails".
+	Path   string
+	Query  url.Values
+	Header http.Header
+	Body   []byte
+	// Fault is the fault injected in the response to the request, if any.
+	Fault *Fault
+}
+
+// Requests returns the requests received so far, in the order they were
+// received, including those which failed.
+func (s *Server) Requests() []Request {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Request(nil), s.requests...)
+}
+
+// Reset forgets all the data, requests and faults of the server.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requests = nil
+	s.faults = nil
+	s.idempotency = map[string]idempotentResponse{}
+	s.emails = nil
+	s.tenants = nil
+}
+
+// A Fault makes a [Server] delay or fail requests.
+type Fault struct {
+	// Method restricts the fault to requests with this method, if set.
+	Method string
+	// Path restricts the fault to requests whose path starts with it, if set,
+	// e.g. "/v1/emails".
+	Path string
+	// Status is the status of the error response to the requests, e.g. 429 or
+	// 503. If zero, the requests are served normally, after Latency.
+	Status int
+	// RetryAfter sets the Retry-After header of the error responses, if positive.
+	RetryAfter time.Duration
+	// Latency delays the responses to the requests.
+	Latency time.Duration
+	// Times is the number of requests the fault applies to, after which it is
+	// removed. If zero, it applies until the faults are cleared.
+	Times int
+
+	matched int
+}
+
+// Inject adds a fault, which applies to the requests matching it from now on.
+// When several faults match a request, the first one injected applies.
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, &f)
+}
+
+// ClearFaults removes all the faults.
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// fault records req and returns the fault which applies to it, if any.
+func (s *Server) fault(r *http.Request, body []byte) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var applied *Fault
+	for i, f := range s.faults {
+		if (f.Method == "" || f.Method == r.Method) && strings.HasPrefix(r.URL.Path, f.Path) {
+			f.matched++
+			if f.Times > 0 && f.matched >= f.Times {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			}
+			c := *f
+			applied = &c
+			break
+		}
+	}
+	s.requests = append(s.requests, Request{
+		Method: r.Method,
+		Path:   r.URL.Path,
+		Query:  r.URL.Query(),
+		Header: r.Header.Clone(),
+		Body:   body,
+		Fault:  applied,
+	})
+	return applied
+}
+
+type idempotentResponse struct {
+	status int
+	body   []byte
+}
+
+// handler handles a request to the API, authenticated as the given tenant, or
+// as the account if tenant is nil. The server is locked while it runs.
+type handler func(w *response, r *http.Request, tenant *tenant)
+
+// response collects the response of a handler, so that it can be replayed for
+// idempotent requests.
+type response struct {
+	requestID string
+	status    int
+	body      []byte
+	// noRetry tells the SDK not to retry the request.
+	noRetry bool
+}
+
+func (w *response) json(status int, data any) {
+	w.status = status
+	w.body, _ = json.Marshal(map[string]any{
+		"success": true,
+		"data":    data,
+		"meta":    map[string]any{"requestId": w.requestID},
+	})
+}
+
+// page responds with the page of items requested by the page and perPage
+// parameters.
+func page[T any](w *response, r *http.Request, items []T) {
+	query := r.URL.Query()
+	p, _ := strconv.Atoi(query.Get("page"))
+	p = max(p, 1)
+	perPage, _ := strconv.Atoi(query.Get("perPage"))
+	if perPage <= 0 {
+		perPage = 30
+	}
+	perPage = min(perPage, 100)
+	start := min((p-1)*perPage, len(items))
+	end := min(start+perPage, len(items))
+	w.status = http.StatusOK
+	w.body, _ = json.Marshal(map[string]any{
+		"success":    true,
+		"data":       append([]T{}, items[start:end]...),
+		"page":       p,
+		"perPage":    perPage,
+		"total":      len(items),
+		"totalPages": (len(items) + perPage - 1) / perPage,
+		"meta":       map[string]any{"requestId": w.requestID},
+	})
+}
+
+func (w *response) error(status int, code, message string, details any) {
+	w.status = status
+	body := map[string]any{
+		"success": false,
+		"error":   map[string]any{"code": code, "message": message},
+		"meta":    map[string]any{"requestId": w.requestID},
+	}
+	if details != nil {
+		body["error"].(map[string]any)["details"] = details
+	}
+	w.body, _ = json.Marshal(body)
+}
+
+func (w *response) notFound(what string) {
+	w.error(http.StatusNotFound, "not_found", what+" not found", nil)
+}
+
+// invalid responds with a validation error, with the messages of fields.
+func (w *response) invalid(fields map[string]string) bool {
+	if len(fields) == 0 {
+		return false
+	}
+	details := map[string][]string{}
+	for field, message := range fields {
+		details[field] = []string{message}
+	}
+	w.error(http.StatusUnprocessableEntity, "validation_error", "Validation failed", details)
+	return true
+}
+
+// decode decodes the JSON body of r into v, and responds with an error if it is
+// invalid.
+func (w *response) decode(r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		w.error(http.StatusBadRequest, "invalid_request", "Invalid JSON body: "+err.Error(), nil)
+		return false
+	}
+	return true
+}
+
+func (s *Server) routes() http.Handler {
+	mux := http.NewServeMux()
+	route := func(pattern string, h handler) {
+		mux.Handle(pattern, s.serve(h))
+	}
+
+	route("GET /v1/emails", s.listEmails)
+	route("POST /v1/emails", s.sendEmail)
+	route("POST /v1/emails/batch", s.sendBatch)
+	route("POST /v1/emails/raw", s.sendRaw)
+	route("GET /v1/emails/{emailId}", s.getEmail)
+	route("GET /v1/emails/{emailId}/deliveries", s.getDeliveries)
+	route("POST /v1/emails/{emailId}/retry", s.retryEmail)
+
+	route("GET /v1/limits", s.getLimits)
+	route("GET /v1/usage", s.getUsage)
+	route("GET /v1/usage/export", s.exportUsage)
+	route("GET /v1/usage/tenants", s.listTenantUsage)
+
+	route("POST /v1/tenants", s.newTenant)
+	route("GET /v1/tenants", s.listTenants)
+	route("GET /v1/tenants/{tenantId}", s.tenant(s.getTenant))
+	route("PATCH /v1/tenants/{tenantId}", s.tenant(s.updateTenant))
+	route("DELETE /v1/tenants/{tenantId}", s.tenant(s.deleteTenant))
+
+	route("POST /v1/tenants/{tenantId}/credentials", s.tenant(s.newCredential))
+	route("GET /v1/tenants/{tenantId}/credentials", s.tenant(s.listCredentials))
+	route("GET /v1/tenants/{tenantId}/credentials/{credentialId}", s.tenant(s.getCredential))
+	route("PATCH /v1/tenants/{tenantId}/credentials/{credentialId}", s.tenant(s.updateCredential))
+	route("DELETE /v1/tenants/{tenantId}/credentials/{credentialId}", s.tenant(s.deleteCredential))
+
+	route("POST /v1/tenants/{tenantId}/domains", s.tenant(s.newDomain))
+	route("GET /v1/tenants/{tenantId}/domains", s.tenant(s.listDomains))
+	route("GET /v1/tenants/{tenantId}/domains/{domainId}", s.tenant(s.getDomain))
+	route("DELETE /v1/tenants/{tenantId}/domains/{domainId}", s.tenant(s.deleteDomain))
+	route("POST /v1/tenants/{tenantId}/domains/{domainId}/verify", s.tenant(s.verifyDomain))
+
+	route("POST /v1/tenants/{tenantId}/suppressions", s.tenant(s.newSuppression))
+	route("GET /v1/tenants/{tenantId}/suppressions", s.tenant(s.listSuppressions))
+	route("GET /v1/tenants/{tenantId}/suppressions/{email}", s.tenant(s.getSuppression))
+	route("DELETE /v1/tenants/{tenantId}/suppressions/{email}", s.tenant(s.deleteSuppression))
+
+	route("POST /v1/tenants/{tenantId}/tracking", s.tenant(s.newTracking))
+	route("GET /v1/tenants/{tenantId}/tracking", s.tenant(s.listTracking))
+	route("GET /v1/tenants/{tenantId}/tracking/{trackingId}", s.tenant(s.getTracking))
+	route("PATCH /v1/tenants/{tenantId}/tracking/{trackingId}", s.tenant(s.updateTracking))
+	route("DELETE /v1/tenants/{tenantId}/tracking/{trackingId}", s.tenant(s.deleteTracking))
+	route("POST /v1/tenants/{tenantId}/tracking/{trackingId}/verify", s.tenant(s.verifyTracking))
+
+	route("POST /v1/tenants/{tenantId}/webhooks", s.tenant(s.newWebhook))
+	route("GET /v1/tenants/{tenantId}/webhooks", s.tenant(s.listWebhooks))
+	route("GET /v1/tenants/{tenantId}/webhooks/{webhookId}", s.tenant(s.getWebhook))
+	route("PATCH /v1/tenants/{tenantId}/webhooks/{webhookId}", s.tenant(s.updateWebhook))
+	route("DELETE /v1/tenants/{tenantId}/webhooks/{webhookId}", s.tenant(s.deleteWebhook))
+	route("POST /v1/tenants/{tenantId}/webhooks/{webhookId}/test", s.tenant(s.testWebhook))
+	route("GET /v1/tenants/{tenantId}/webhooks/{webhookId}/deliveries", s.tenant(s.listWebhookDeliveries))
+	route("GET /v1/tenants/{tenantId}/webhooks/{webhookId}/deliveries/{deliveryId}", s.tenant(s.getWebhookDelivery))
+	route("POST /v1/tenants/{tenantId}/webhooks/{webhookId}/deliveries/{deliveryId}/replay", s.tenant(s.replayWebhookDelivery))
+
+	route("GET /v1/tenants/{tenantId}/usage", s.tenant(s.getTenantUsage))
+	route("GET /v1/tenants/{tenantId}/usage/timeseries", s.tenant(s.getTenantTimeseries))
+
+	route("/", func(w *response, r *http.Request, _ *tenant) {
+		w.noRetry = true
+		w.error(http.StatusNotImplemented, "not_implemented", fmt.Sprintf("%s %s is not implemented by arktest", r.Method, r.URL.Path), nil)
+	})
+	return mux
+}
+
+// tenant wraps a handler of the tenant of the tenantId path parameter, which is
+// passed in place of the authenticated tenant. Tenants can only be managed with
+// the API key of the account.
+func (s *Server) tenant(h handler) handler {
+	return func(w *response, r *http.Request, auth *tenant) {
+		if auth != nil {
+			w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot manage tenants", nil)
+			return
+		}
+		t := s.findTenant(r.PathValue("tenantId"))
+		if t == nil {
+			w.notFound("Tenant")
+			return
+		}
+		h(w, r, t)
+	}
+}
+
+// serve wraps a handler with the behavior shared by all endpoints: recording
+// requests, injecting faults, authentication and idempotency.
+func (s *Server) serve(h handler) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(rw, err.Error(), http.StatusBadRequest)
+			return
+		}
+		r.Body = io.NopCloser(bytes.NewReader(body))
+		w := &response{requestID: "req_" + randomHex(12)}
+
+		fault := s.fault(r, body)
+		if fault != nil && fault.Latency > 0 {
+			select {
+			case <-time.After(fault.Latency):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if fault != nil && fault.Status != 0 {
+			if fault.RetryAfter > 0 {
+				rw.Header().Set("Retry-After", strconv.Itoa(int((fault.RetryAfter+time.Second-1)/time.Second)))
+			}
+			code := strings.ReplaceAll(strings.ToLower(http.StatusText(fault.Status)), " ", "_")
+			w.error(fault.Status, code, "Injected fault", nil)
+			s.write(rw, w)
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		auth, ok := s.authenticate(r)
+		if !ok {
+			w.error(http.StatusUnauthorized, "unauthorized", "Invalid API key", nil)
+			s.write(rw, w)
+			return
+		}
+		key := r.Header.Get("Idempotency-Key")
+		if key != "" && r.Method == http.MethodPost {
+			key = r.Header.Get("Authorization") + " " + r.URL.Path + " " + key
+			if res, ok := s.idempotency[key]; ok {
+				w.status, w.body = res.status, res.body
+				s.write(rw, w)
+				return
+			}
+		}
+		h(w, r, auth)
+		if key != "" && r.Method == http.MethodPost && w.status < 500 {
+			s.idempotency[key] = idempotentResponse{w.status, w.body}
+		}
+		s.write(rw, w)
+	})
+}
+
+func (s *Server) write(rw http.ResponseWriter, w *response) {
+	rw.Header().Set("Content-Type", "application/json")
+	rw.Header().Set("X-Request-Id", w.requestID)
+	if w.noRetry {
+		rw.Header().Set("X-Should-Retry", "false")
+	}
+	rw.WriteHeader(w.status)
+	rw.Write(w.body)
+}
+
+// authenticate returns the tenant authenticated by the API key of r, which is
+// nil for the API key of the account.
+func (s *Server) authenticate(r *http.Request) (*tenant, bool) {
+	key, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || key == "" {
+		return nil, false
+	}
+	if key == s.APIKey {
+		return nil, true
+	}
+	for _, t := range s.tenants {
+		for _, c := range t.credentials {
+			if c.Type == "api" && c.Key == key && !c.Hold {
+				c.LastUsedAt = s.now()
+				return t, true
+			}
+		}
+	}
+	return nil, false
+}
+
+// nextID returns a new ID, unique within the server.
+func (s *Server) nextID() int64 {
+	s.seq++
+	return s.seq
+}
+
+func randomHex(n int) string {
+	b := make([]byte, n/2)
+	rand.Read(b)
+	return fmt.Sprintf("%x", b)
+}
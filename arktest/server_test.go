@@ -0,0 +1,829 @@
+package arktest_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/arktest"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+func TestEmails(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	attachment, err := ark.AttachmentFromBytes("invoice.txt", []byte("total: 42"))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	params := ark.EmailSendParams{
+		From:           "Shop <hello@yourdomain.com>",
+		To:             []string{"user@example.com"},
+		Subject:        "Your invoice",
+		HTML:           ark.String("<p>See attached</p>"),
+		Tag:            ark.String("invoice"),
+		Metadata:       map[string]string{"order_id": "42"},
+		Attachments:    []ark.EmailSendParamsAttachment{attachment},
+		IdempotencyKey: ark.String("order-42"),
+	}
+	sent, err := client.Emails.Send(ctx, params)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	// The same idempotency key returns the same email, without sending it again.
+	again, err := client.Emails.Send(ctx, params)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if again.Data.ID != sent.Data.ID {
+		t.Fatalf("expected the idempotent request to return %s, got %s", sent.Data.ID, again.Data.ID)
+	}
+	_, err = client.Emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@yourdomain.com",
+		To:      []string{"other@example.com"},
+		Subject: "Welcome",
+		Text:    ark.String("Hi"),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	emails := srv.SentEmails()
+	if len(emails) != 2 {
+		t.Fatalf("expected 2 emails, got %d", len(emails))
+	}
+	if e := emails[0]; e.ID != sent.Data.ID || e.Subject != "Your invoice" || e.Metadata["order_id"] != "42" ||
+		len(e.Attachments) != 1 || string(e.Attachments[0].Content) != "total: 42" {
+		t.Fatalf("unexpected email %+v", e)
+	}
+
+	got, err := client.Emails.Get(ctx, sent.Data.ID, ark.EmailGetParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if got.Data.To != "user@example.com" || got.Data.HTMLBody != "<p>See attached</p>" || got.Data.Status != "sent" ||
+		len(got.Data.Attachments) != 1 || len(got.Data.Deliveries) != 1 {
+		t.Fatalf("unexpected email %s", got.RawJSON())
+	}
+
+	list, err := client.Emails.List(ctx, ark.EmailListParams{Tag: ark.String("invoice")})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if list.Total != 1 || len(list.Data) != 1 || list.Data[0].ID != sent.Data.ID {
+		t.Fatalf("unexpected list %s", list.RawJSON())
+	}
+
+	_, err = client.Emails.Get(ctx, "msg_missing", ark.EmailGetParams{})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	_, err = client.Emails.Send(ctx, ark.EmailSendParams{From: "hello@yourdomain.com", To: []string{"not an address"}, Subject: "Hi"})
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || len(apierr.ValidationError().Fields) != 2 {
+		t.Fatalf("expected a validation error for to.0 and html, got %v", err)
+	}
+}
+
+func TestEmailBatchAndRaw(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	batch, err := client.Emails.SendBatch(ctx, ark.EmailSendBatchParams{
+		From: "hello@yourdomain.com",
+		Emails: []ark.EmailSendBatchParamsEmail{
+			{To: []string{"a@example.com"}, Subject: "A", Text: ark.String("a")},
+			{To: []string{"b@example.com"}, Subject: "B"},
+		},
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if batch.Data.Accepted != 1 || batch.Data.Failed != 1 || batch.Data.Messages["a@example.com"].ID == "" {
+		t.Fatalf("unexpected batch response %s", batch.RawJSON())
+	}
+
+	message := "Subject: Raw hello\r\n\r\nHello"
+	_, err = client.Emails.SendRaw(ctx, ark.EmailSendRawParams{
+		From:       "hello@yourdomain.com",
+		To:         []string{"c@example.com"},
+		RawMessage: base64.StdEncoding.EncodeToString([]byte(message)),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	emails := srv.SentEmails()
+	if len(emails) != 2 || !emails[0].Batch || emails[1].Subject != "Raw hello" || string(emails[1].RawMessage) != message {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+}
+
+func TestTenants(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantID := tenant.Data.ID
+
+	domain, err := client.Tenants.Domains.New(ctx, tenantID, ark.TenantDomainNewParams{Name: "acme.com"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if domain.Data.Verified || domain.Data.DNSRecords.Spf.FullName != "acme.com" {
+		t.Fatalf("unexpected domain %s", domain.RawJSON())
+	}
+	verified, err := client.Tenants.Domains.Verify(ctx, "999", ark.TenantDomainVerifyParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound for an unknown domain, got %v, %v", verified, err)
+	}
+	domainID := strconv.FormatInt(domain.Data.ID, 10)
+	verified, err = client.Tenants.Domains.Verify(ctx, domainID, ark.TenantDomainVerifyParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !verified.Data.Verified || verified.Data.DNSRecords.Dkim.Status != ark.DNSRecordStatusOk {
+		t.Fatalf("unexpected domain %s", verified.RawJSON())
+	}
+
+	tracking, err := client.Tenants.Tracking.New(ctx, tenantID, ark.TenantTrackingNewParams{DomainID: domain.Data.ID, Name: "track"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if tracking.Data.FullName != "track.acme.com" {
+		t.Fatalf("unexpected track domain %s", tracking.RawJSON())
+	}
+
+	_, err = client.Tenants.Suppressions.New(ctx, tenantID, ark.TenantSuppressionNewParams{Address: "bounced@example.com", Reason: ark.String("hard bounce")})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	suppression, err := client.Tenants.Suppressions.Get(ctx, "bounced@example.com", ark.TenantSuppressionGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !suppression.Data.Suppressed || suppression.Data.Reason != "hard bounce" {
+		t.Fatalf("unexpected suppression %s", suppression.RawJSON())
+	}
+
+	webhook, err := client.Tenants.Webhooks.New(ctx, tenantID, ark.TenantWebhookNewParams{
+		Name:   "Events",
+		URL:    "https://acme.com/webhooks",
+		Events: []string{"MessageSent"},
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Tenants.Webhooks.Test(ctx, webhook.Data.ID, ark.TenantWebhookTestParams{TenantID: tenantID, Event: "MessageSent"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	deliveries, err := client.Tenants.Webhooks.ListDeliveries(ctx, webhook.Data.ID, ark.TenantWebhookListDeliveriesParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(deliveries.Data) != 1 || deliveries.Data[0].Event != "MessageSent" {
+		t.Fatalf("unexpected deliveries %s", deliveries.RawJSON())
+	}
+
+	// Emails sent with an API credential of the tenant count in its usage.
+	credential, err := client.Tenants.Credentials.New(ctx, tenantID, ark.TenantCredentialNewParams{Name: "app", Type: "api"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantClient := srv.Client(option.WithAPIKey(credential.Data.Key))
+	_, err = tenantClient.Emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@acme.com",
+		To:      []string{"user@example.com"},
+		Subject: "Hi",
+		Text:    ark.String("Hi"),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if emails := srv.SentEmails(); len(emails) != 1 || emails[0].TenantID != tenantID {
+		t.Fatalf("expected an email of the tenant, got %+v", emails)
+	}
+	usage, err := client.Tenants.Usage.Get(ctx, tenantID, ark.TenantUsageGetParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if usage.Data.Emails.Sent != 1 || usage.Data.Emails.Delivered != 1 || usage.Data.Rates.DeliveryRate != 1 {
+		t.Fatalf("unexpected usage %s", usage.RawJSON())
+	}
+	limits, err := client.Limits.Get(ctx)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if limits.Data.SendLimit.Used != 1 {
+		t.Fatalf("unexpected limits %s", limits.RawJSON())
+	}
+
+	_, err = tenantClient.Tenants.Get(ctx, tenantID)
+	if err == nil {
+		t.Fatalf("expected tenant credentials not to manage tenants")
+	}
+	_, err = client.Tenants.Delete(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = tenantClient.Limits.Get(ctx)
+	if !errors.Is(err, ark.ErrUnauthorized) {
+		t.Fatalf("expected the credential to be deleted with its tenant, got %v", err)
+	}
+}
+
+func TestFaults(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client(option.WithRetryPolicy(option.FixedBackoff{Delay: time.Millisecond}))
+	ctx := context.Background()
+
+	srv.Inject(arktest.Fault{Path: "/v1/limits", Status: http.StatusServiceUnavailable, Times: 2})
+	if _, err := client.Limits.Get(ctx); err != nil {
+		t.Fatalf("expected the request to succeed on its third attempt, got %v", err)
+	}
+	requests := srv.Requests()
+	if len(requests) != 3 || requests[0].Fault == nil || requests[2].Fault != nil {
+		t.Fatalf("unexpected requests %+v", requests)
+	}
+
+	srv.Inject(arktest.Fault{Method: http.MethodPost, Path: "/v1/emails", Status: http.StatusTooManyRequests, RetryAfter: time.Second})
+	_, err := client.Emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@yourdomain.com",
+		To:      []string{"user@example.com"},
+		Subject: "Hi",
+		Text:    ark.String("Hi"),
+	}, option.WithMaxRetries(0))
+	var apierr *ark.Error
+	if !errors.Is(err, ark.ErrRateLimited) || !errors.As(err, &apierr) || apierr.Response.Header.Get("Retry-After") != "1" {
+		t.Fatalf("expected a rate limit error, got %v", err)
+	}
+	srv.ClearFaults()
+
+	srv.Inject(arktest.Fault{Latency: time.Second})
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.Limits.Get(ctx, option.WithMaxRetries(0)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to time out, got %v", err)
+	}
+}
+
+func TestAuthentication(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+
+	client := srv.Client(option.WithAPIKey("wrong"))
+	_, err := client.Limits.Get(context.Background())
+	if !errors.Is(err, ark.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+	client = srv.Client()
+	_, err = client.Logs.Get(context.Background(), "req_123")
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusNotImplemented {
+		t.Fatalf("expected logs not to be implemented, got %v", err)
+	}
+}
+
+func TestEmailStatus(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	sent, err := client.Emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@yourdomain.com",
+		To:      []string{"user@example.com"},
+		Subject: "Hi",
+		Text:    ark.String("Hi"),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Emails.Retry(ctx, sent.Data.ID)
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a sent email not to be retried, got %v", err)
+	}
+
+	if err := srv.SetEmailStatus(sent.Data.ID, "hardfail"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if err := srv.SetEmailStatus("msg_missing", "hardfail"); err == nil {
+		t.Fatalf("expected an error for an unknown email")
+	}
+	deliveries, err := client.Emails.GetDeliveries(ctx, sent.Data.ID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if deliveries.Data.Status != "hardfail" || !deliveries.Data.CanRetryManually || len(deliveries.Data.Deliveries) != 0 {
+		t.Fatalf("unexpected deliveries %s", deliveries.RawJSON())
+	}
+	retry, err := client.Emails.Retry(ctx, sent.Data.ID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if retry.Data.ID != sent.Data.ID {
+		t.Fatalf("unexpected retry %s", retry.RawJSON())
+	}
+	if emails := srv.SentEmails(); emails[0].Status != "pending" {
+		t.Fatalf("expected the retried email to be pending, got %s", emails[0].Status)
+	}
+	if _, err := client.Emails.Retry(ctx, "msg_missing"); !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestTenantUpdates(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	for _, name := range []string{"Acme", "Globex"} {
+		if _, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: name}); err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+	}
+	list, err := client.Tenants.List(ctx, ark.TenantListParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if list.Total != 2 || len(list.Data) != 2 || list.Data[0].Name != "Acme" {
+		t.Fatalf("unexpected list %s", list.RawJSON())
+	}
+
+	updated, err := client.Tenants.Update(ctx, list.Data[1].ID, ark.TenantUpdateParams{
+		Name:   ark.String("Globex Corp"),
+		Status: ark.TenantUpdateParamsStatusSuspended,
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if updated.Data.Name != "Globex Corp" || updated.Data.Status != "suspended" {
+		t.Fatalf("unexpected tenant %s", updated.RawJSON())
+	}
+	list, err = client.Tenants.List(ctx, ark.TenantListParams{Status: ark.TenantListParamsStatusSuspended})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != updated.Data.ID {
+		t.Fatalf("unexpected list %s", list.RawJSON())
+	}
+
+	_, err = client.Tenants.Update(ctx, updated.Data.ID, ark.TenantUpdateParams{Name: ark.String("")})
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if _, err := client.Tenants.Update(ctx, "tn_missing", ark.TenantUpdateParams{}); !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestCredentials(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantID := tenant.Data.ID
+	credential, err := client.Tenants.Credentials.New(ctx, tenantID, ark.TenantCredentialNewParams{Name: "app", Type: "api"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Tenants.Credentials.New(ctx, tenantID, ark.TenantCredentialNewParams{Name: "relay", Type: "smtp"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	list, err := client.Tenants.Credentials.List(ctx, tenantID, ark.TenantCredentialListParams{Type: ark.TenantCredentialListParamsTypeAPI})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(list.Data) != 1 || list.Data[0].ID != credential.Data.ID {
+		t.Fatalf("unexpected list %s", list.RawJSON())
+	}
+	got, err := client.Tenants.Credentials.Get(ctx, credential.Data.ID, ark.TenantCredentialGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if got.Data.Name != "app" || got.Data.Key != "" {
+		t.Fatalf("expected the key to be hidden, got %s", got.RawJSON())
+	}
+	got, err = client.Tenants.Credentials.Get(ctx, credential.Data.ID, ark.TenantCredentialGetParams{TenantID: tenantID, Reveal: ark.Bool(true)})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if got.Data.Key != credential.Data.Key {
+		t.Fatalf("expected the key to be revealed, got %s", got.RawJSON())
+	}
+
+	// A credential on hold cannot authenticate.
+	tenantClient := srv.Client(option.WithAPIKey(credential.Data.Key))
+	updated, err := client.Tenants.Credentials.Update(ctx, credential.Data.ID, ark.TenantCredentialUpdateParams{
+		TenantID: tenantID,
+		Name:     ark.String("app v2"),
+		Hold:     ark.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if updated.Data.Name != "app v2" || !updated.Data.Hold {
+		t.Fatalf("unexpected credential %s", updated.RawJSON())
+	}
+	if _, err := tenantClient.Limits.Get(ctx); !errors.Is(err, ark.ErrUnauthorized) {
+		t.Fatalf("expected ErrUnauthorized, got %v", err)
+	}
+
+	deleted, err := client.Tenants.Credentials.Delete(ctx, credential.Data.ID, ark.TenantCredentialDeleteParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !deleted.Data.Deleted {
+		t.Fatalf("unexpected response %s", deleted.RawJSON())
+	}
+	_, err = client.Tenants.Credentials.Get(ctx, credential.Data.ID, ark.TenantCredentialGetParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestDomainsAndTracking(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantID := tenant.Data.ID
+	domain, err := client.Tenants.Domains.New(ctx, tenantID, ark.TenantDomainNewParams{Name: "acme.com"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	domainID := strconv.FormatInt(domain.Data.ID, 10)
+
+	domains, err := client.Tenants.Domains.List(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(domains.Data.Domains) != 1 || domains.Data.Domains[0].Name != "acme.com" || domains.Data.Domains[0].TenantID != tenantID {
+		t.Fatalf("unexpected domains %s", domains.RawJSON())
+	}
+	got, err := client.Tenants.Domains.Get(ctx, domainID, ark.TenantDomainGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if got.Data.Name != "acme.com" || got.Data.DNSRecords.Dkim.Value == "" {
+		t.Fatalf("unexpected domain %s", got.RawJSON())
+	}
+
+	tracking, err := client.Tenants.Tracking.New(ctx, tenantID, ark.TenantTrackingNewParams{DomainID: domain.Data.ID, Name: "track"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	trackingID := tracking.Data.ID
+	trackings, err := client.Tenants.Tracking.List(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(trackings.Data.TrackDomains) != 1 || trackings.Data.TrackDomains[0].ID != trackingID {
+		t.Fatalf("unexpected track domains %s", trackings.RawJSON())
+	}
+	updated, err := client.Tenants.Tracking.Update(ctx, trackingID, ark.TenantTrackingUpdateParams{
+		TenantID:             tenantID,
+		TrackOpens:           ark.Bool(false),
+		ExcludedClickDomains: ark.String("example.com"),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if updated.Data.TrackOpens || !updated.Data.TrackClicks || updated.Data.ExcludedClickDomains != "example.com" {
+		t.Fatalf("unexpected track domain %s", updated.RawJSON())
+	}
+	verified, err := client.Tenants.Tracking.Verify(ctx, trackingID, ark.TenantTrackingVerifyParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !verified.Data.DNSOk {
+		t.Fatalf("unexpected verification %s", verified.RawJSON())
+	}
+	gotTracking, err := client.Tenants.Tracking.Get(ctx, trackingID, ark.TenantTrackingGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if !gotTracking.Data.DNSOk || gotTracking.Data.TrackOpens {
+		t.Fatalf("unexpected track domain %s", gotTracking.RawJSON())
+	}
+	if _, err := client.Tenants.Tracking.Delete(ctx, trackingID, ark.TenantTrackingDeleteParams{TenantID: tenantID}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Tenants.Tracking.Get(ctx, trackingID, ark.TenantTrackingGetParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	// Deleting a domain deletes its track domains.
+	tracking, err = client.Tenants.Tracking.New(ctx, tenantID, ark.TenantTrackingNewParams{DomainID: domain.Data.ID, Name: "links"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	deleted, err := client.Tenants.Domains.Delete(ctx, domainID, ark.TenantDomainDeleteParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if deleted.Data.Message != "Domain deleted" {
+		t.Fatalf("unexpected response %s", deleted.RawJSON())
+	}
+	_, err = client.Tenants.Domains.Get(ctx, domainID, ark.TenantDomainGetParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	_, err = client.Tenants.Tracking.Get(ctx, tracking.Data.ID, ark.TenantTrackingGetParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected the track domain to be deleted with its domain, got %v", err)
+	}
+}
+
+func TestSuppressions(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantID := tenant.Data.ID
+	_, err = client.Tenants.Suppressions.New(ctx, tenantID, ark.TenantSuppressionNewParams{Address: "bounced@example.com"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	list, err := client.Tenants.Suppressions.List(ctx, tenantID, ark.TenantSuppressionListParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(list.Data) != 1 || list.Data[0].Address != "bounced@example.com" {
+		t.Fatalf("unexpected list %s", list.RawJSON())
+	}
+	_, err = client.Tenants.Suppressions.Delete(ctx, "bounced@example.com", ark.TenantSuppressionDeleteParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	suppression, err := client.Tenants.Suppressions.Get(ctx, "bounced@example.com", ark.TenantSuppressionGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if suppression.Data.Suppressed {
+		t.Fatalf("expected the suppression to be removed, got %s", suppression.RawJSON())
+	}
+	_, err = client.Tenants.Suppressions.Delete(ctx, "bounced@example.com", ark.TenantSuppressionDeleteParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestWebhooks(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantID := tenant.Data.ID
+	webhook, err := client.Tenants.Webhooks.New(ctx, tenantID, ark.TenantWebhookNewParams{
+		Name: "Events",
+		URL:  "https://acme.com/webhooks",
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	webhookID := webhook.Data.ID
+
+	webhooks, err := client.Tenants.Webhooks.List(ctx, tenantID)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(webhooks.Data.Webhooks) != 1 || webhooks.Data.Webhooks[0].ID != webhookID {
+		t.Fatalf("unexpected webhooks %s", webhooks.RawJSON())
+	}
+	updated, err := client.Tenants.Webhooks.Update(ctx, webhookID, ark.TenantWebhookUpdateParams{
+		TenantID:  tenantID,
+		URL:       ark.String("https://acme.com/ark"),
+		Events:    []string{"MessageBounced"},
+		AllEvents: ark.Bool(false),
+		Enabled:   ark.Bool(false),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if updated.Data.URL != "https://acme.com/ark" || updated.Data.AllEvents || updated.Data.Enabled || len(updated.Data.Events) != 1 {
+		t.Fatalf("unexpected webhook %s", updated.RawJSON())
+	}
+	_, err = client.Tenants.Webhooks.Update(ctx, webhookID, ark.TenantWebhookUpdateParams{TenantID: tenantID, Events: []string{"Unknown"}})
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusUnprocessableEntity {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	got, err := client.Tenants.Webhooks.Get(ctx, webhookID, ark.TenantWebhookGetParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if got.Data.URL != "https://acme.com/ark" {
+		t.Fatalf("unexpected webhook %s", got.RawJSON())
+	}
+
+	_, err = client.Tenants.Webhooks.Test(ctx, webhookID, ark.TenantWebhookTestParams{TenantID: tenantID, Event: "MessageBounced"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	deliveries, err := client.Tenants.Webhooks.ListDeliveries(ctx, webhookID, ark.TenantWebhookListDeliveriesParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(deliveries.Data) != 1 {
+		t.Fatalf("unexpected deliveries %s", deliveries.RawJSON())
+	}
+	deliveryID := deliveries.Data[0].ID
+	delivery, err := client.Tenants.Webhooks.GetDelivery(ctx, deliveryID, ark.TenantWebhookGetDeliveryParams{TenantID: tenantID, WebhookID: webhookID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if delivery.Data.Event != "MessageBounced" || delivery.Data.WebhookName != "Events" || !delivery.Data.Success {
+		t.Fatalf("unexpected delivery %s", delivery.RawJSON())
+	}
+	replay, err := client.Tenants.Webhooks.ReplayDelivery(ctx, deliveryID, ark.TenantWebhookReplayDeliveryParams{TenantID: tenantID, WebhookID: webhookID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if replay.Data.OriginalDeliveryID != deliveryID || replay.Data.NewDeliveryID == deliveryID || !replay.Data.Success {
+		t.Fatalf("unexpected replay %s", replay.RawJSON())
+	}
+	deliveries, err = client.Tenants.Webhooks.ListDeliveries(ctx, webhookID, ark.TenantWebhookListDeliveriesParams{TenantID: tenantID})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(deliveries.Data) != 2 || deliveries.Data[0].ID != replay.Data.NewDeliveryID {
+		t.Fatalf("expected the replay first, got %s", deliveries.RawJSON())
+	}
+	_, err = client.Tenants.Webhooks.GetDelivery(ctx, "whd_missing", ark.TenantWebhookGetDeliveryParams{TenantID: tenantID, WebhookID: webhookID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+
+	if _, err := client.Tenants.Webhooks.Delete(ctx, webhookID, ark.TenantWebhookDeleteParams{TenantID: tenantID}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Tenants.Webhooks.Get(ctx, webhookID, ark.TenantWebhookGetParams{TenantID: tenantID})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	var tenantIDs []string
+	for _, name := range []string{"Acme", "Globex"} {
+		tenant, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: name})
+		if err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+		tenantIDs = append(tenantIDs, tenant.Data.ID)
+	}
+	credential, err := client.Tenants.Credentials.New(ctx, tenantIDs[1], ark.TenantCredentialNewParams{Name: "app", Type: "api"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	tenantClient := srv.Client(option.WithAPIKey(credential.Data.Key))
+	srv.SetSendLimit(2)
+	for i := range 3 {
+		_, err := tenantClient.Emails.Send(ctx, ark.EmailSendParams{
+			From:    "hello@globex.com",
+			To:      []string{"user@example.com"},
+			Subject: "Hi",
+			Text:    ark.String("Hi"),
+		}, option.WithMaxRetries(0))
+		if i < 2 && err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+		if i == 2 && !errors.Is(err, ark.ErrRateLimited) {
+			t.Fatalf("expected the send limit to be exceeded, got %v", err)
+		}
+	}
+	if _, err := tenantClient.Usage.Get(ctx, ark.UsageGetParams{}); err == nil {
+		t.Fatalf("expected tenant credentials not to read the usage of the account")
+	}
+
+	usage, err := client.Usage.Get(ctx, ark.UsageGetParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if usage.Data.Emails.Sent != 2 || usage.Data.Tenants.Total != 2 || usage.Data.Tenants.WithActivity != 1 {
+		t.Fatalf("unexpected usage %s", usage.RawJSON())
+	}
+
+	tenants, err := client.Usage.ListTenants(ctx, ark.UsageListTenantsParams{Sort: ark.UsageListTenantsParamsSortTenantName})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(tenants.Data) != 2 || tenants.Data[0].TenantName != "Acme" || tenants.Data[1].Emails.Sent != 2 {
+		t.Fatalf("unexpected tenant usage %s", tenants.RawJSON())
+	}
+	tenants, err = client.Usage.ListTenants(ctx, ark.UsageListTenantsParams{MinSent: ark.Int(1)})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(tenants.Data) != 1 || tenants.Data[0].TenantID != tenantIDs[1] {
+		t.Fatalf("unexpected tenant usage %s", tenants.RawJSON())
+	}
+
+	export, err := client.Usage.Export(ctx, ark.UsageExportParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(*export) != 2 || (*export)[0].TenantID != tenantIDs[1] || (*export)[0].Sent != 2 || (*export)[0].DeliveryRate != 1 {
+		t.Fatalf("unexpected export %+v", *export)
+	}
+
+	timeseries, err := client.Tenants.Usage.GetTimeseries(ctx, tenantIDs[1], ark.TenantUsageGetTimeseriesParams{
+		Granularity: ark.TenantUsageGetTimeseriesParamsGranularityDay,
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	var sent int64
+	for _, point := range timeseries.Data.Data {
+		sent += point.Sent
+	}
+	if timeseries.Data.Granularity != "day" || len(timeseries.Data.Data) == 0 || sent != 2 {
+		t.Fatalf("unexpected timeseries %s", timeseries.RawJSON())
+	}
+}
+
+func TestReset(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	client := srv.Client()
+	ctx := context.Background()
+
+	if _, err := client.Tenants.New(ctx, ark.TenantNewParams{Name: "Acme"}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err := client.Emails.Send(ctx, ark.EmailSendParams{
+		From:    "hello@yourdomain.com",
+		To:      []string{"user@example.com"},
+		Subject: "Hi",
+		Text:    ark.String("Hi"),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	srv.Inject(arktest.Fault{Path: "/v1/limits", Status: http.StatusServiceUnavailable})
+
+	srv.Reset()
+	if emails, requests := srv.SentEmails(), srv.Requests(); len(emails) != 0 || len(requests) != 0 {
+		t.Fatalf("expected no emails and requests, got %d and %d", len(emails), len(requests))
+	}
+	if _, err := client.Limits.Get(ctx); err != nil {
+		t.Fatalf("expected the faults to be cleared, got %v", err)
+	}
+	tenants, err := client.Tenants.List(ctx, ark.TenantListParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(tenants.Data) != 0 {
+		t.Fatalf("expected no tenants, got %s", tenants.RawJSON())
+	}
+}
@@ -0,0 +1,634 @@
+package arktest
+
+import (
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/uuid"
+)
+
+type tenant struct {
+	ID        string         `json:"id"`
+	Name      string         `json:"name"`
+	Metadata  map[string]any `json:"metadata"`
+	Status    string         `json:"status"`
+	CreatedAt time.Time      `json:"createdAt"`
+	UpdatedAt time.Time      `json:"updatedAt"`
+
+	credentials  []*credential
+	domains      []*domain
+	suppressions []*suppression
+	tracking     []*trackDomain
+	webhooks     []*webhook
+	deliveries   []*webhookDelivery
+}
+
+func (s *Server) findTenant(id string) *tenant {
+	for _, t := range s.tenants {
+		if t.ID == id {
+			return t
+		}
+	}
+	return nil
+}
+
+func validateMetadata(fields map[string]string, metadata map[string]any) {
+	for key, value := range metadata {
+		switch value.(type) {
+		case string, float64, bool:
+		default:
+			fields["metadata."+key] = "must be a string, number or boolean"
+		}
+	}
+}
+
+func validTenantStatus(status string) bool {
+	return status == "active" || status == "suspended" || status == "archived"
+}
+
+func (s *Server) newTenant(w *response, r *http.Request, auth *tenant) {
+	if auth != nil {
+		w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot manage tenants", nil)
+		return
+	}
+	var body struct {
+		Name     string         `json:"name"`
+		Metadata map[string]any `json:"metadata"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	if body.Name == "" {
+		fields["name"] = "is required"
+	}
+	validateMetadata(fields, body.Metadata)
+	if w.invalid(fields) {
+		return
+	}
+	now := s.now()
+	t := &tenant{
+		ID:        fmt.Sprintf("tn_%d", s.nextID()),
+		Name:      body.Name,
+		Metadata:  body.Metadata,
+		Status:    "active",
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if t.Metadata == nil {
+		t.Metadata = map[string]any{}
+	}
+	s.tenants = append(s.tenants, t)
+	w.json(http.StatusCreated, t)
+}
+
+func (s *Server) listTenants(w *response, r *http.Request, auth *tenant) {
+	if auth != nil {
+		w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot manage tenants", nil)
+		return
+	}
+	status := r.URL.Query().Get("status")
+	var items []*tenant
+	for _, t := range s.tenants {
+		if status == "" || t.Status == status {
+			items = append(items, t)
+		}
+	}
+	page(w, r, items)
+}
+
+func (s *Server) getTenant(w *response, r *http.Request, t *tenant) {
+	w.json(http.StatusOK, t)
+}
+
+func (s *Server) updateTenant(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		Name     *string        `json:"name"`
+		Metadata map[string]any `json:"metadata"`
+		Status   *string        `json:"status"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	if body.Name != nil && *body.Name == "" {
+		fields["name"] = "must not be empty"
+	}
+	if body.Status != nil && !validTenantStatus(*body.Status) {
+		fields["status"] = "must be one of active, suspended, archived"
+	}
+	validateMetadata(fields, body.Metadata)
+	if w.invalid(fields) {
+		return
+	}
+	if body.Name != nil {
+		t.Name = *body.Name
+	}
+	if body.Status != nil {
+		t.Status = *body.Status
+	}
+	if body.Metadata != nil {
+		t.Metadata = body.Metadata
+	}
+	t.UpdatedAt = s.now()
+	w.json(http.StatusOK, t)
+}
+
+func (s *Server) deleteTenant(w *response, r *http.Request, t *tenant) {
+	s.tenants = slices.DeleteFunc(s.tenants, func(other *tenant) bool { return other == t })
+	w.json(http.StatusOK, map[string]any{"deleted": true})
+}
+
+type credential struct {
+	ID         int64
+	Name       string
+	Type       string
+	Key        string
+	Hold       bool
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	LastUsedAt time.Time
+}
+
+// data returns the JSON object of c, which only includes its key if reveal.
+func (c *credential) data(t *tenant, reveal bool) map[string]any {
+	data := map[string]any{
+		"id":         c.ID,
+		"name":       c.Name,
+		"type":       c.Type,
+		"hold":       c.Hold,
+		"createdAt":  c.CreatedAt,
+		"updatedAt":  c.UpdatedAt,
+		"lastUsedAt": c.LastUsedAt,
+	}
+	if reveal {
+		data["key"] = c.Key
+	}
+	if c.Type == "smtp" {
+		data["smtpUsername"] = t.ID + "/" + c.Name
+	}
+	return data
+}
+
+// pathID parses the int64 ID of the path parameter name.
+func pathID(r *http.Request, name string) int64 {
+	id, err := strconv.ParseInt(r.PathValue(name), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return id
+}
+
+func (t *tenant) findCredential(r *http.Request) *credential {
+	id := pathID(r, "credentialId")
+	for _, c := range t.credentials {
+		if c.ID == id {
+			return c
+		}
+	}
+	return nil
+}
+
+func (s *Server) newCredential(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	if body.Name == "" {
+		fields["name"] = "is required"
+	}
+	if body.Type != "smtp" && body.Type != "api" {
+		fields["type"] = "must be one of smtp, api"
+	}
+	if w.invalid(fields) {
+		return
+	}
+	now := s.now()
+	c := &credential{
+		ID:        s.nextID(),
+		Name:      body.Name,
+		Type:      body.Type,
+		Key:       "ark_" + body.Type + "_" + randomHex(32),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	t.credentials = append(t.credentials, c)
+	w.json(http.StatusCreated, c.data(t, true))
+}
+
+func (s *Server) listCredentials(w *response, r *http.Request, t *tenant) {
+	typ := r.URL.Query().Get("type")
+	var items []map[string]any
+	for _, c := range t.credentials {
+		if typ == "" || c.Type == typ {
+			items = append(items, c.data(t, false))
+		}
+	}
+	page(w, r, items)
+}
+
+func (s *Server) getCredential(w *response, r *http.Request, t *tenant) {
+	c := t.findCredential(r)
+	if c == nil {
+		w.notFound("Credential")
+		return
+	}
+	w.json(http.StatusOK, c.data(t, r.URL.Query().Get("reveal") == "true"))
+}
+
+func (s *Server) updateCredential(w *response, r *http.Request, t *tenant) {
+	c := t.findCredential(r)
+	if c == nil {
+		w.notFound("Credential")
+		return
+	}
+	var body struct {
+		Name *string `json:"name"`
+		Hold *bool   `json:"hold"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	if body.Name != nil && *body.Name == "" && w.invalid(map[string]string{"name": "must not be empty"}) {
+		return
+	}
+	if body.Name != nil {
+		c.Name = *body.Name
+	}
+	if body.Hold != nil {
+		c.Hold = *body.Hold
+	}
+	c.UpdatedAt = s.now()
+	w.json(http.StatusOK, c.data(t, false))
+}
+
+func (s *Server) deleteCredential(w *response, r *http.Request, t *tenant) {
+	c := t.findCredential(r)
+	if c == nil {
+		w.notFound("Credential")
+		return
+	}
+	t.credentials = slices.DeleteFunc(t.credentials, func(other *credential) bool { return other == c })
+	w.json(http.StatusOK, map[string]any{"deleted": true})
+}
+
+type domain struct {
+	ID         int64
+	UUID       string
+	Name       string
+	Verified   bool
+	CreatedAt  time.Time
+	VerifiedAt time.Time
+	selector   string
+	dkimKey    string
+}
+
+func (d *domain) data(t *tenant) map[string]any {
+	status := any(nil)
+	if d.Verified {
+		status = "OK"
+	}
+	record := func(typ, name, value string) map[string]any {
+		fullName := d.Name
+		if name != "@" {
+			fullName = name + "." + d.Name
+		}
+		return map[string]any{"type": typ, "name": name, "fullName": fullName, "value": value, "status": status}
+	}
+	data := map[string]any{
+		"id":          d.ID,
+		"uuid":        d.UUID,
+		"name":        d.Name,
+		"verified":    d.Verified,
+		"createdAt":   d.CreatedAt,
+		"tenant_id":   t.ID,
+		"tenant_name": t.Name,
+		"dnsRecords": map[string]any{
+			"zone":       d.Name,
+			"spf":        record("TXT", "@", "v=spf1 include:spf.arkhq.io ~all"),
+			"dkim":       record("TXT", d.selector+"._domainkey", "v=DKIM1; t=s; h=sha256; p="+d.dkimKey),
+			"returnPath": record("CNAME", "psrp", "rp.arkhq.io"),
+		},
+	}
+	if d.Verified {
+		data["verifiedAt"] = d.VerifiedAt
+	}
+	return data
+}
+
+func (t *tenant) findDomain(r *http.Request) *domain {
+	id := pathID(r, "domainId")
+	for _, d := range t.domains {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *Server) newDomain(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		Name string `json:"name"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	name := strings.ToLower(body.Name)
+	fields := map[string]string{}
+	if !strings.Contains(name, ".") || strings.ContainsAny(name, "@/ ") {
+		fields["name"] = "must be a domain name"
+	}
+	for _, d := range t.domains {
+		if d.Name == name {
+			fields["name"] = "has already been added"
+		}
+	}
+	if w.invalid(fields) {
+		return
+	}
+	d := &domain{
+		ID:        s.nextID(),
+		UUID:      uuid.New(),
+		Name:      name,
+		CreatedAt: s.now(),
+		selector:  "ark-" + randomHex(6),
+		dkimKey:   randomHex(32),
+	}
+	t.domains = append(t.domains, d)
+	w.json(http.StatusCreated, d.data(t))
+}
+
+func (s *Server) listDomains(w *response, r *http.Request, t *tenant) {
+	domains := []map[string]any{}
+	for _, d := range t.domains {
+		domains = append(domains, map[string]any{
+			"id":          d.ID,
+			"name":        d.Name,
+			"verified":    d.Verified,
+			"tenant_id":   t.ID,
+			"tenant_name": t.Name,
+		})
+	}
+	w.json(http.StatusOK, map[string]any{"domains": domains})
+}
+
+func (s *Server) getDomain(w *response, r *http.Request, t *tenant) {
+	d := t.findDomain(r)
+	if d == nil {
+		w.notFound("Domain")
+		return
+	}
+	w.json(http.StatusOK, d.data(t))
+}
+
+func (s *Server) deleteDomain(w *response, r *http.Request, t *tenant) {
+	d := t.findDomain(r)
+	if d == nil {
+		w.notFound("Domain")
+		return
+	}
+	t.domains = slices.DeleteFunc(t.domains, func(other *domain) bool { return other == d })
+	t.tracking = slices.DeleteFunc(t.tracking, func(td *trackDomain) bool { return td.DomainID == strconv.FormatInt(d.ID, 10) })
+	w.json(http.StatusOK, map[string]any{"message": "Domain deleted"})
+}
+
+// verifyDomain verifies a domain, as if its DNS records were configured.
+func (s *Server) verifyDomain(w *response, r *http.Request, t *tenant) {
+	d := t.findDomain(r)
+	if d == nil {
+		w.notFound("Domain")
+		return
+	}
+	if !d.Verified {
+		d.Verified = true
+		d.VerifiedAt = s.now()
+	}
+	w.json(http.StatusOK, d.data(t))
+}
+
+type suppression struct {
+	ID        string    `json:"id"`
+	Address   string    `json:"address"`
+	Reason    string    `json:"reason"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// findSuppression returns the suppression of the address of the email path
+// parameter.
+func (t *tenant) findSuppression(r *http.Request) *suppression {
+	address, _ := url.PathUnescape(r.PathValue("email"))
+	for _, sup := range t.suppressions {
+		if strings.EqualFold(sup.Address, address) {
+			return sup
+		}
+	}
+	return nil
+}
+
+func (s *Server) newSuppression(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		Address string `json:"address"`
+		Reason  string `json:"reason"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	if _, err := mail.ParseAddress(body.Address); err != nil && w.invalid(map[string]string{"address": "is not a valid email address"}) {
+		return
+	}
+	for _, sup := range t.suppressions {
+		if strings.EqualFold(sup.Address, body.Address) {
+			sup.Reason = body.Reason
+			w.json(http.StatusOK, sup)
+			return
+		}
+	}
+	sup := &suppression{
+		ID:        fmt.Sprintf("sup_%d", s.nextID()),
+		Address:   body.Address,
+		Reason:    body.Reason,
+		CreatedAt: s.now(),
+	}
+	t.suppressions = append(t.suppressions, sup)
+	w.json(http.StatusCreated, sup)
+}
+
+func (s *Server) listSuppressions(w *response, r *http.Request, t *tenant) {
+	page(w, r, t.suppressions)
+}
+
+func (s *Server) getSuppression(w *response, r *http.Request, t *tenant) {
+	address, _ := url.PathUnescape(r.PathValue("email"))
+	data := map[string]any{"address": address, "suppressed": false}
+	if sup := t.findSuppression(r); sup != nil {
+		data["suppressed"] = true
+		data["reason"] = sup.Reason
+		data["createdAt"] = sup.CreatedAt
+	}
+	w.json(http.StatusOK, data)
+}
+
+func (s *Server) deleteSuppression(w *response, r *http.Request, t *tenant) {
+	sup := t.findSuppression(r)
+	if sup == nil {
+		w.notFound("Suppression")
+		return
+	}
+	t.suppressions = slices.DeleteFunc(t.suppressions, func(other *suppression) bool { return other == sup })
+	w.json(http.StatusOK, map[string]any{"message": "Suppression removed"})
+}
+
+type trackDomain struct {
+	ID                   string     `json:"id"`
+	DomainID             string     `json:"domainId"`
+	Name                 string     `json:"name"`
+	FullName             string     `json:"fullName"`
+	DNSOk                bool       `json:"dnsOk"`
+	DNSStatus            *string    `json:"dnsStatus"`
+	DNSCheckedAt         *time.Time `json:"dnsCheckedAt"`
+	DNSRecord            any        `json:"dnsRecord"`
+	SslEnabled           bool       `json:"sslEnabled"`
+	TrackClicks          bool       `json:"trackClicks"`
+	TrackOpens           bool       `json:"trackOpens"`
+	ExcludedClickDomains *string    `json:"excludedClickDomains"`
+	CreatedAt            time.Time  `json:"createdAt"`
+	UpdatedAt            time.Time  `json:"updatedAt"`
+}
+
+func (t *tenant) findTracking(r *http.Request) *trackDomain {
+	for _, td := range t.tracking {
+		if td.ID == r.PathValue("trackingId") {
+			return td
+		}
+	}
+	return nil
+}
+
+func (s *Server) newTracking(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		DomainID    int64  `json:"domainId"`
+		Name        string `json:"name"`
+		SslEnabled  *bool  `json:"sslEnabled"`
+		TrackClicks *bool  `json:"trackClicks"`
+		TrackOpens  *bool  `json:"trackOpens"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	if body.Name == "" || strings.ContainsAny(body.Name, "./@ ") {
+		fields["name"] = "must be a subdomain name, e.g. track"
+	}
+	var d *domain
+	for _, candidate := range t.domains {
+		if candidate.ID == body.DomainID {
+			d = candidate
+		}
+	}
+	if d == nil {
+		fields["domainId"] = "must be a domain of the tenant"
+	}
+	if w.invalid(fields) {
+		return
+	}
+	now := s.now()
+	td := &trackDomain{
+		ID:          fmt.Sprintf("trk_%d", s.nextID()),
+		DomainID:    strconv.FormatInt(d.ID, 10),
+		Name:        body.Name,
+		FullName:    body.Name + "." + d.Name,
+		SslEnabled:  body.SslEnabled == nil || *body.SslEnabled,
+		TrackClicks: body.TrackClicks == nil || *body.TrackClicks,
+		TrackOpens:  body.TrackOpens == nil || *body.TrackOpens,
+		DNSRecord:   map[string]any{"type": "CNAME", "name": body.Name, "value": "track.arkhq.io"},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	t.tracking = append(t.tracking, td)
+	w.json(http.StatusCreated, td)
+}
+
+func (s *Server) listTracking(w *response, r *http.Request, t *tenant) {
+	w.json(http.StatusOK, map[string]any{"trackDomains": append([]*trackDomain{}, t.tracking...)})
+}
+
+func (s *Server) getTracking(w *response, r *http.Request, t *tenant) {
+	td := t.findTracking(r)
+	if td == nil {
+		w.notFound("Track domain")
+		return
+	}
+	w.json(http.StatusOK, td)
+}
+
+func (s *Server) updateTracking(w *response, r *http.Request, t *tenant) {
+	td := t.findTracking(r)
+	if td == nil {
+		w.notFound("Track domain")
+		return
+	}
+	var body struct {
+		ExcludedClickDomains *string `json:"excludedClickDomains"`
+		SslEnabled           *bool   `json:"sslEnabled"`
+		TrackClicks          *bool   `json:"trackClicks"`
+		TrackOpens           *bool   `json:"trackOpens"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	if body.ExcludedClickDomains != nil {
+		td.ExcludedClickDomains = body.ExcludedClickDomains
+	}
+	if body.SslEnabled != nil {
+		td.SslEnabled = *body.SslEnabled
+	}
+	if body.TrackClicks != nil {
+		td.TrackClicks = *body.TrackClicks
+	}
+	if body.TrackOpens != nil {
+		td.TrackOpens = *body.TrackOpens
+	}
+	td.UpdatedAt = s.now()
+	w.json(http.StatusOK, td)
+}
+
+func (s *Server) deleteTracking(w *response, r *http.Request, t *tenant) {
+	td := t.findTracking(r)
+	if td == nil {
+		w.notFound("Track domain")
+		return
+	}
+	t.tracking = slices.DeleteFunc(t.tracking, func(other *trackDomain) bool { return other == td })
+	w.json(http.StatusOK, map[string]any{"message": "Track domain deleted"})
+}
+
+// verifyTracking verifies a tracking domain, as if its CNAME record was
+// configured.
+func (s *Server) verifyTracking(w *response, r *http.Request, t *tenant) {
+	td := t.findTracking(r)
+	if td == nil {
+		w.notFound("Track domain")
+		return
+	}
+	now := s.now()
+	status := "ok"
+	td.DNSOk, td.DNSStatus, td.DNSCheckedAt = true, &status, &now
+	w.json(http.StatusOK, map[string]any{
+		"id":           td.ID,
+		"fullName":     td.FullName,
+		"dnsOk":        td.DNSOk,
+		"dnsStatus":    status,
+		"dnsCheckedAt": now,
+		"dnsRecord":    td.DNSRecord,
+	})
+}
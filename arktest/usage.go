@@ -0,0 +1,296 @@
+package arktest
+
+import (
+	"cmp"
+	"encoding/json"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// emailCounts counts emails by outcome, like the usage endpoints. Every email
+// counts as sent, and as delivered while its status is "sent".
+type emailCounts struct {
+	Sent       int64 `json:"sent"`
+	Delivered  int64 `json:"delivered"`
+	SoftFailed int64 `json:"soft_failed"`
+	HardFailed int64 `json:"hard_failed"`
+	Bounced    int64 `json:"bounced"`
+	Held       int64 `json:"held"`
+}
+
+func (c *emailCounts) add(e *Email) {
+	c.Sent++
+	switch e.Status {
+	case "sent":
+		c.Delivered++
+	case "softfail":
+		c.SoftFailed++
+	case "hardfail":
+		c.HardFailed++
+	case "bounced":
+		c.Bounced++
+	case "held":
+		c.Held++
+	}
+}
+
+func (c emailCounts) rates() map[string]float64 {
+	rates := map[string]float64{"delivery_rate": 0, "bounce_rate": 0}
+	if c.Sent > 0 {
+		rates["delivery_rate"] = float64(c.Delivered) / float64(c.Sent)
+		rates["bounce_rate"] = float64(c.Bounced) / float64(c.Sent)
+	}
+	return rates
+}
+
+// usagePeriod returns the period of usage reports. The fake ignores the period
+// parameter, and always reports on the current month.
+func (s *Server) usagePeriod() (start, end time.Time) {
+	end = s.now().UTC()
+	return time.Date(end.Year(), end.Month(), 1, 0, 0, 0, 0, time.UTC), end
+}
+
+// countEmails counts the emails sent in the usage period, by the tenant with the
+// given ID if it is not empty.
+func (s *Server) countEmails(tenantID string) emailCounts {
+	start, _ := s.usagePeriod()
+	var counts emailCounts
+	for _, e := range s.emails {
+		if !e.SentAt.Before(start) && (tenantID == "" || e.TenantID == tenantID) {
+			counts.add(e)
+		}
+	}
+	return counts
+}
+
+func (s *Server) periodData() map[string]any {
+	start, end := s.usagePeriod()
+	return map[string]any{"start": start, "end": end}
+}
+
+func (s *Server) getUsage(w *response, r *http.Request, auth *tenant) {
+	if auth != nil {
+		w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot read the usage of the account", nil)
+		return
+	}
+	counts := s.countEmails("")
+	var active, withActivity int64
+	for _, t := range s.tenants {
+		if t.Status == "active" {
+			active++
+		}
+		if s.countEmails(t.ID).Sent > 0 {
+			withActivity++
+		}
+	}
+	w.json(http.StatusOK, map[string]any{
+		"emails": counts,
+		"rates":  counts.rates(),
+		"period": s.periodData(),
+		"tenants": map[string]any{
+			"total":        len(s.tenants),
+			"active":       active,
+			"withActivity": withActivity,
+		},
+	})
+}
+
+type tenantUsage struct {
+	tenant *tenant
+	counts emailCounts
+}
+
+// tenantUsages returns the usage of the tenants matching the status, minSent
+// and sort parameters.
+func (s *Server) tenantUsages(r *http.Request) []tenantUsage {
+	query := r.URL.Query()
+	minSent, _ := strconv.ParseInt(query.Get("minSent"), 10, 64)
+	var usages []tenantUsage
+	for _, t := range s.tenants {
+		u := tenantUsage{t, s.countEmails(t.ID)}
+		if query.Has("status") && t.Status != query.Get("status") || u.counts.Sent < minSent {
+			continue
+		}
+		usages = append(usages, u)
+	}
+	sort := cmp.Or(query.Get("sort"), "-sent")
+	field, desc := strings.CutPrefix(sort, "-")
+	slices.SortStableFunc(usages, func(a, b tenantUsage) int {
+		var c int
+		switch field {
+		case "delivered":
+			c = cmp.Compare(a.counts.Delivered, b.counts.Delivered)
+		case "delivery_rate":
+			c = cmp.Compare(a.counts.rates()["delivery_rate"], b.counts.rates()["delivery_rate"])
+		case "bounce_rate":
+			c = cmp.Compare(a.counts.rates()["bounce_rate"], b.counts.rates()["bounce_rate"])
+		case "tenant_name":
+			c = strings.Compare(a.tenant.Name, b.tenant.Name)
+		default:
+			c = cmp.Compare(a.counts.Sent, b.counts.Sent)
+		}
+		if desc {
+			return -c
+		}
+		return c
+	})
+	return usages
+}
+
+func (s *Server) listTenantUsage(w *response, r *http.Request, auth *tenant) {
+	if auth != nil {
+		w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot read the usage of the account", nil)
+		return
+	}
+	var items []map[string]any
+	for _, u := range s.tenantUsages(r) {
+		items = append(items, map[string]any{
+			"tenantId":   u.tenant.ID,
+			"tenantName": u.tenant.Name,
+			"status":     u.tenant.Status,
+			"emails":     u.counts,
+			"rates":      u.counts.rates(),
+		})
+	}
+	page(w, r, items)
+}
+
+// exportUsage responds with the usage of the tenants as a JSON array, whatever
+// the format parameter.
+func (s *Server) exportUsage(w *response, r *http.Request, auth *tenant) {
+	if auth != nil {
+		w.error(http.StatusForbidden, "forbidden", "Tenant credentials cannot read the usage of the account", nil)
+		return
+	}
+	rows := []map[string]any{}
+	for _, u := range s.tenantUsages(r) {
+		rates := u.counts.rates()
+		rows = append(rows, map[string]any{
+			"tenant_id":     u.tenant.ID,
+			"tenant_name":   u.tenant.Name,
+			"status":        u.tenant.Status,
+			"sent":          u.counts.Sent,
+			"delivered":     u.counts.Delivered,
+			"soft_failed":   u.counts.SoftFailed,
+			"hard_failed":   u.counts.HardFailed,
+			"bounced":       u.counts.Bounced,
+			"held":          u.counts.Held,
+			"delivery_rate": rates["delivery_rate"],
+			"bounce_rate":   rates["bounce_rate"],
+		})
+	}
+	w.status = http.StatusOK
+	w.body, _ = json.Marshal(rows)
+}
+
+func (s *Server) getTenantUsage(w *response, r *http.Request, t *tenant) {
+	counts := s.countEmails(t.ID)
+	w.json(http.StatusOK, map[string]any{
+		"tenant_id":   t.ID,
+		"tenant_name": t.Name,
+		"emails":      counts,
+		"rates":       counts.rates(),
+		"period":      s.periodData(),
+	})
+}
+
+func (s *Server) getTenantTimeseries(w *response, r *http.Request, t *tenant) {
+	granularity := cmp.Or(r.URL.Query().Get("granularity"), "day")
+	var truncate func(time.Time) time.Time
+	var next func(time.Time) time.Time
+	switch granularity {
+	case "hour":
+		truncate = func(t time.Time) time.Time { return t.Truncate(time.Hour) }
+		next = func(t time.Time) time.Time { return t.Add(time.Hour) }
+	case "day":
+		truncate = startOfDay
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 1) }
+	case "week":
+		truncate = func(t time.Time) time.Time {
+			day := startOfDay(t)
+			return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+		}
+		next = func(t time.Time) time.Time { return t.AddDate(0, 0, 7) }
+	case "month":
+		truncate = func(t time.Time) time.Time { return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC) }
+		next = func(t time.Time) time.Time { return t.AddDate(0, 1, 0) }
+	default:
+		w.invalid(map[string]string{"granularity": "must be one of hour, day, week, month"})
+		return
+	}
+
+	start, end := s.usagePeriod()
+	var buckets []time.Time
+	counts := map[time.Time]*emailCounts{}
+	for bucket := truncate(start); bucket.Before(end); bucket = next(bucket) {
+		buckets = append(buckets, bucket)
+		counts[bucket] = &emailCounts{}
+	}
+	for _, e := range s.emails {
+		if c, ok := counts[truncate(e.SentAt.UTC())]; ok && e.TenantID == t.ID {
+			c.add(e)
+		}
+	}
+	points := make([]map[string]any, len(buckets))
+	for i, bucket := range buckets {
+		c := counts[bucket]
+		points[i] = map[string]any{
+			"timestamp":   bucket,
+			"sent":        c.Sent,
+			"delivered":   c.Delivered,
+			"soft_failed": c.SoftFailed,
+			"hard_failed": c.HardFailed,
+			"bounced":     c.Bounced,
+			"held":        c.Held,
+		}
+	}
+	w.json(http.StatusOK, map[string]any{
+		"tenant_id":   t.ID,
+		"tenant_name": t.Name,
+		"granularity": granularity,
+		"period":      s.periodData(),
+		"data":        points,
+	})
+}
+
+// getLimits reports the send limit of the account, of which the emails sent
+// today are used, and a fixed rate limit which the fake does not enforce.
+func (s *Server) getLimits(w *response, r *http.Request, auth *tenant) {
+	now := s.now().UTC()
+	used := s.sentSince(startOfDay(now))
+	w.json(http.StatusOK, map[string]any{
+		"rateLimit": map[string]any{
+			"limit":     100,
+			"remaining": 100,
+			"period":    "second",
+			"reset":     now.Add(time.Second).Unix(),
+		},
+		"sendLimit": map[string]any{
+			"limit":        s.sendLimit,
+			"used":         used,
+			"remaining":    max(s.sendLimit-used, 0),
+			"period":       "day",
+			"resetsAt":     startOfDay(now).AddDate(0, 0, 1),
+			"usagePercent": float64(used) / float64(s.sendLimit) * 100,
+			"approaching":  used*10 >= s.sendLimit*9,
+			"exceeded":     used >= s.sendLimit,
+		},
+		"billing": map[string]any{
+			"creditBalance":      "100.00",
+			"creditBalanceCents": 10000,
+			"hasPaymentMethod":   true,
+			"autoRecharge":       map[string]any{"enabled": false, "amount": "0.00", "threshold": "0.00"},
+		},
+	})
+}
+
+// SetSendLimit sets the number of emails which can be sent per day, after which
+// sending fails with 429 Too Many Requests. It defaults to 10000.
+func (s *Server) SetSendLimit(limit int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sendLimit = limit
+}
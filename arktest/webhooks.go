@@ -0,0 +1,292 @@
+package arktest
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/uuid"
+)
+
+// webhookEvents are the events webhooks can subscribe to.
+var webhookEvents = []string{
+	"MessageSent",
+	"MessageDelayed",
+	"MessageDeliveryFailed",
+	"MessageHeld",
+	"MessageBounced",
+	"MessageLinkClicked",
+	"MessageLoaded",
+	"DomainDNSError",
+}
+
+type webhook struct {
+	ID        string    `json:"id"`
+	UUID      string    `json:"uuid"`
+	Name      string    `json:"name"`
+	URL       string    `json:"url"`
+	Events    []string  `json:"events"`
+	AllEvents bool      `json:"allEvents"`
+	Enabled   bool      `json:"enabled"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// webhookDelivery is a delivery of a test event. The fake does not call
+// webhooks, so every delivery succeeds.
+type webhookDelivery struct {
+	ID         string    `json:"id"`
+	WebhookID  string    `json:"webhookId"`
+	Event      string    `json:"event"`
+	URL        string    `json:"url"`
+	Attempt    int       `json:"attempt"`
+	StatusCode int       `json:"statusCode"`
+	Success    bool      `json:"success"`
+	WillRetry  bool      `json:"willRetry"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+func (t *tenant) findWebhook(r *http.Request) *webhook {
+	for _, wh := range t.webhooks {
+		if wh.ID == r.PathValue("webhookId") {
+			return wh
+		}
+	}
+	return nil
+}
+
+func validateWebhook(fields map[string]string, name, rawURL *string, events []string) {
+	if name != nil && *name == "" {
+		fields["name"] = "is required"
+	}
+	if rawURL != nil {
+		if u, err := url.Parse(*rawURL); err != nil || u.Scheme != "https" && u.Scheme != "http" || u.Host == "" {
+			fields["url"] = "must be an HTTP(S) URL"
+		}
+	}
+	for i, event := range events {
+		if !slices.Contains(webhookEvents, event) {
+			fields[fmt.Sprintf("events.%d", i)] = "is not a webhook event"
+		}
+	}
+}
+
+func (s *Server) newWebhook(w *response, r *http.Request, t *tenant) {
+	var body struct {
+		Name      string   `json:"name"`
+		URL       string   `json:"url"`
+		Events    []string `json:"events"`
+		AllEvents *bool    `json:"allEvents"`
+		Enabled   *bool    `json:"enabled"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	validateWebhook(fields, &body.Name, &body.URL, body.Events)
+	if w.invalid(fields) {
+		return
+	}
+	wh := &webhook{
+		ID:        fmt.Sprintf("wh_%d", s.nextID()),
+		UUID:      uuid.New(),
+		Name:      body.Name,
+		URL:       body.URL,
+		Events:    body.Events,
+		AllEvents: body.AllEvents != nil && *body.AllEvents || len(body.Events) == 0,
+		Enabled:   body.Enabled == nil || *body.Enabled,
+		CreatedAt: s.now(),
+	}
+	if wh.Events == nil {
+		wh.Events = []string{}
+	}
+	t.webhooks = append(t.webhooks, wh)
+	w.json(http.StatusCreated, wh)
+}
+
+func (s *Server) listWebhooks(w *response, r *http.Request, t *tenant) {
+	w.json(http.StatusOK, map[string]any{"webhooks": append([]*webhook{}, t.webhooks...)})
+}
+
+func (s *Server) getWebhook(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	w.json(http.StatusOK, wh)
+}
+
+func (s *Server) updateWebhook(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	var body struct {
+		Name      *string  `json:"name"`
+		URL       *string  `json:"url"`
+		Events    []string `json:"events"`
+		AllEvents *bool    `json:"allEvents"`
+		Enabled   *bool    `json:"enabled"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	fields := map[string]string{}
+	validateWebhook(fields, body.Name, body.URL, body.Events)
+	if w.invalid(fields) {
+		return
+	}
+	if body.Name != nil {
+		wh.Name = *body.Name
+	}
+	if body.URL != nil {
+		wh.URL = *body.URL
+	}
+	if body.Events != nil {
+		wh.Events = body.Events
+	}
+	if body.AllEvents != nil {
+		wh.AllEvents = *body.AllEvents
+	}
+	if body.Enabled != nil {
+		wh.Enabled = *body.Enabled
+	}
+	w.json(http.StatusOK, wh)
+}
+
+func (s *Server) deleteWebhook(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	t.webhooks = slices.DeleteFunc(t.webhooks, func(other *webhook) bool { return other == wh })
+	w.json(http.StatusOK, map[string]any{"message": "Webhook deleted"})
+}
+
+// testWebhook records a successful delivery of a test event, without calling
+// the webhook.
+func (s *Server) testWebhook(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	var body struct {
+		Event string `json:"event"`
+	}
+	if !w.decode(r, &body) {
+		return
+	}
+	if !slices.Contains(webhookEvents, body.Event) && w.invalid(map[string]string{"event": "is not a webhook event"}) {
+		return
+	}
+	d := s.deliver(t, wh, body.Event)
+	w.json(http.StatusOK, map[string]any{
+		"event":      d.Event,
+		"statusCode": d.StatusCode,
+		"success":    d.Success,
+		"duration":   0,
+		"body":       "",
+	})
+}
+
+func (s *Server) deliver(t *tenant, wh *webhook, event string) *webhookDelivery {
+	d := &webhookDelivery{
+		ID:         fmt.Sprintf("whd_%d", s.nextID()),
+		WebhookID:  wh.ID,
+		Event:      event,
+		URL:        wh.URL,
+		Attempt:    1,
+		StatusCode: http.StatusOK,
+		Success:    true,
+		Timestamp:  s.now(),
+	}
+	t.deliveries = append(t.deliveries, d)
+	return d
+}
+
+func (t *tenant) findDelivery(r *http.Request, wh *webhook) *webhookDelivery {
+	for _, d := range t.deliveries {
+		if d.ID == r.PathValue("deliveryId") && d.WebhookID == wh.ID {
+			return d
+		}
+	}
+	return nil
+}
+
+func (s *Server) listWebhookDeliveries(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	query := r.URL.Query()
+	var items []*webhookDelivery
+	for i := len(t.deliveries) - 1; i >= 0; i-- {
+		d := t.deliveries[i]
+		switch {
+		case d.WebhookID != wh.ID,
+			query.Has("event") && d.Event != query.Get("event"),
+			query.Has("success") && fmt.Sprint(d.Success) != query.Get("success"):
+			continue
+		}
+		items = append(items, d)
+	}
+	page(w, r, items)
+}
+
+func (s *Server) getWebhookDelivery(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	d := t.findDelivery(r, wh)
+	if d == nil {
+		w.notFound("Delivery")
+		return
+	}
+	w.json(http.StatusOK, map[string]any{
+		"id":          d.ID,
+		"webhookId":   d.WebhookID,
+		"webhookName": wh.Name,
+		"event":       d.Event,
+		"url":         d.URL,
+		"attempt":     d.Attempt,
+		"statusCode":  d.StatusCode,
+		"success":     d.Success,
+		"willRetry":   d.WillRetry,
+		"timestamp":   d.Timestamp,
+		"request": map[string]any{
+			"headers": map[string]string{"Content-Type": "application/json"},
+			"payload": map[string]any{"event": d.Event, "timestamp": unixSeconds(d.Timestamp), "uuid": d.ID},
+		},
+		"response": map[string]any{"statusCode": d.StatusCode, "body": ""},
+	})
+}
+
+func (s *Server) replayWebhookDelivery(w *response, r *http.Request, t *tenant) {
+	wh := t.findWebhook(r)
+	if wh == nil {
+		w.notFound("Webhook")
+		return
+	}
+	d := t.findDelivery(r, wh)
+	if d == nil {
+		w.notFound("Delivery")
+		return
+	}
+	replay := s.deliver(t, wh, d.Event)
+	w.json(http.StatusOK, map[string]any{
+		"originalDeliveryId": d.ID,
+		"newDeliveryId":      replay.ID,
+		"statusCode":         replay.StatusCode,
+		"success":            replay.Success,
+		"duration":           0,
+		"timestamp":          replay.Timestamp,
+	})
+}
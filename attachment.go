@@ -0,0 +1,92 @@
+package ark
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"os"
+	"path/filepath"
+)
+
+// ErrMessageTooLarge is returned when an email, including its attachments, would
+// exceed the 14MB limit of the API.
+var ErrMessageTooLarge = errors.New("message exceeds the 14MB size limit")
+
+// AttachmentFromBytes returns an attachment with the given filename and content.
+// The content type is detected from the extension of filename, or sniffed from
+// content if the extension is unknown.
+func AttachmentFromBytes(filename string, content []byte) (EmailSendParamsAttachment, error) {
+	if len(content) > maxMessageSize {
+		return EmailSendParamsAttachment{}, fmt.Errorf("attachment %q: %w", filename, ErrMessageTooLarge)
+	}
+	return EmailSendParamsAttachment{
+		Content:     base64.StdEncoding.EncodeToString(content),
+		ContentType: detectContentType(filename, content),
+		Filename:    filename,
+	}, nil
+}
+
+// AttachmentFromReader reads r until EOF and returns an attachment with its
+// content, see [AttachmentFromBytes]. Reading stops with [ErrMessageTooLarge] as
+// soon as the content exceeds the size limit.
+func AttachmentFromReader(filename string, r io.Reader) (EmailSendParamsAttachment, error) {
+	content, err := io.ReadAll(io.LimitReader(r, maxMessageSize+1))
+	if err != nil {
+		return EmailSendParamsAttachment{}, fmt.Errorf("attachment %q: %w", filename, err)
+	}
+	return AttachmentFromBytes(filename, content)
+}
+
+// AttachmentFromFile returns an attachment with the content of the file at path,
+// named after its base name, see [AttachmentFromBytes].
+func AttachmentFromFile(path string) (EmailSendParamsAttachment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return EmailSendParamsAttachment{}, err
+	}
+	defer f.Close()
+	filename := filepath.Base(path)
+	if info, err := f.Stat(); err == nil && info.Size() > maxMessageSize {
+		return EmailSendParamsAttachment{}, fmt.Errorf("attachment %q: %w", filename, ErrMessageTooLarge)
+	}
+	return AttachmentFromReader(filename, f)
+}
+
+// Attach adds attachments to the email, returning [ErrMessageTooLarge] without
+// adding any of them if the email would exceed the 14MB size limit.
+func (r *EmailSendParams) Attach(attachments ...EmailSendParamsAttachment) error {
+	size := r.size()
+	for _, a := range attachments {
+		size += a.size()
+	}
+	if size > maxMessageSize {
+		return fmt.Errorf("%w: the email would be %d bytes", ErrMessageTooLarge, size)
+	}
+	r.Attachments = append(r.Attachments, attachments...)
+	return nil
+}
+
+// size returns the approximate size of the email, i.e. of its bodies and
+// decoded attachments.
+func (r EmailSendParams) size() int {
+	size := len(r.HTML.Value) + len(r.Text.Value)
+	for _, a := range r.Attachments {
+		size += a.size()
+	}
+	return size
+}
+
+// size returns the decoded size of the attachment content.
+func (r EmailSendParamsAttachment) size() int {
+	return base64.StdEncoding.DecodedLen(len(r.Content))
+}
+
+func detectContentType(filename string, content []byte) string {
+	if typ := mime.TypeByExtension(filepath.Ext(filename)); typ != "" {
+		return typ
+	}
+	return http.DetectContentType(content)
+}
@@ -0,0 +1,85 @@
+package ark_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+)
+
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestAttachmentFromBytes(t *testing.T) {
+	tests := map[string]struct {
+		filename    string
+		content     []byte
+		contentType string
+	}{
+		"extension": {"report.pdf", []byte("%PDF-1.7"), "application/pdf"},
+		"sniffed":   {"logo", pngHeader, "image/png"},
+		"text":      {"notes", []byte("hello"), "text/plain; charset=utf-8"},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			a, err := ark.AttachmentFromBytes(test.filename, test.content)
+			if err != nil {
+				t.Fatalf("err should be nil: %s", err.Error())
+			}
+			if a.Filename != test.filename || a.ContentType != test.contentType {
+				t.Fatalf("unexpected attachment %+v", a)
+			}
+			if decoded, _ := base64.StdEncoding.DecodeString(a.Content); !bytes.Equal(decoded, test.content) {
+				t.Fatalf("expected the content to be base64-encoded, got %q", a.Content)
+			}
+		})
+	}
+}
+
+func TestAttachmentFromFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "logo.png")
+	if err := os.WriteFile(path, pngHeader, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	a, err := ark.AttachmentFromFile(path)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if a.Filename != "logo.png" || a.ContentType != "image/png" {
+		t.Fatalf("unexpected attachment %+v", a)
+	}
+
+	if _, err := ark.AttachmentFromFile(filepath.Join(t.TempDir(), "missing.png")); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected os.ErrNotExist, got %v", err)
+	}
+}
+
+func TestAttachmentFromReaderTooLarge(t *testing.T) {
+	r := bytes.NewReader(make([]byte, 14<<20+1))
+	if _, err := ark.AttachmentFromReader("big.bin", r); !errors.Is(err, ark.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if r.Len() != 0 {
+		t.Fatalf("expected reading to stop at the limit, %d bytes left", r.Len())
+	}
+}
+
+func TestEmailSendParamsAttach(t *testing.T) {
+	params := ark.EmailSendParams{HTML: ark.String(strings.Repeat("a", 4<<20))}
+	small, _ := ark.AttachmentFromBytes("small.bin", make([]byte, 8<<20))
+	big, _ := ark.AttachmentFromBytes("big.bin", make([]byte, 4<<20))
+
+	if err := params.Attach(small); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if err := params.Attach(big); !errors.Is(err, ark.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if len(params.Attachments) != 1 {
+		t.Fatalf("expected only the first attachment to be added, got %d", len(params.Attachments))
+	}
+}
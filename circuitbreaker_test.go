@@ -0,0 +1,143 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// switchableAPI answers requests with status, counting them per path.
+type switchableAPI struct {
+	status   int
+	requests map[string]int
+}
+
+func (a *switchableAPI) serve(req *http.Request) (*http.Response, error) {
+	a.requests[req.URL.Host+req.URL.Path]++
+	return jsonResponse(a.status, `{}`), nil
+}
+
+func TestCircuitBreaker(t *testing.T) {
+	clock := newFakeClock()
+	var changes []string
+	breaker := option.WithCircuitBreaker(option.CircuitBreakerConfig{
+		ConsecutiveFailures: 3,
+		OpenTimeout:         10 * time.Second,
+		Clock:               clock,
+		OnStateChange: func(key string, from, to option.CircuitState) {
+			changes = append(changes, fmt.Sprintf("%s: %s -> %s", key, from, to))
+		},
+	})
+	api := &switchableAPI{status: http.StatusServiceUnavailable, requests: map[string]int{}}
+	client := newTestClient(api.serve, option.WithBaseURL("https://api.arkhq.io/v1/"), breaker, option.WithClock(clock))
+	other := newTestClient(api.serve, option.WithBaseURL("https://eu.arkhq.io/v1/"), breaker, option.WithMaxRetries(0))
+
+	// The first request is attempted 3 times, which opens the breaker.
+	_, err := client.Logs.Get(context.Background(), "req_1")
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the error of the API, got %v", err)
+	}
+	_, err = client.Logs.Get(context.Background(), "req_2")
+	var openErr *option.CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected the breaker to be open, got %v", err)
+	}
+	if openErr.Key != "api.arkhq.io GET /v1/logs/{id}" || !openErr.Until.Equal(clock.Now().Add(10*time.Second)) {
+		t.Fatalf("unexpected error %+v", openErr)
+	}
+	if api.requests["api.arkhq.io/v1/logs/req_2"] != 0 {
+		t.Fatalf("expected the request not to be sent")
+	}
+	if len(clock.Waits()) != 2 {
+		t.Fatalf("expected the open breaker not to be retried, got waits %v", clock.Waits())
+	}
+
+	// Other operations and hosts have their own breaker.
+	if _, err := client.Emails.Get(context.Background(), "msg_1", ark.EmailGetParams{}, option.WithMaxRetries(0)); errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected the breaker of other operations to be closed")
+	}
+	if _, err := other.Logs.Get(context.Background(), "req_3"); errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected the breaker of other hosts to be closed")
+	}
+
+	// After the timeout, a probe closes the breaker again.
+	clock.Advance(10 * time.Second)
+	api.status = http.StatusOK
+	if _, err := client.Logs.Get(context.Background(), "req_4"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if _, err := client.Logs.Get(context.Background(), "req_5"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	want := []string{
+		"api.arkhq.io GET /v1/logs/{id}: closed -> open",
+		"api.arkhq.io GET /v1/logs/{id}: open -> half-open",
+		"api.arkhq.io GET /v1/logs/{id}: half-open -> closed",
+	}
+	if !reflect.DeepEqual(changes, want) {
+		t.Fatalf("expected state changes %v, got %v", want, changes)
+	}
+}
+
+func TestCircuitBreakerHalfOpenFailure(t *testing.T) {
+	clock := newFakeClock()
+	api := &switchableAPI{status: http.StatusBadGateway, requests: map[string]int{}}
+	client := newTestClient(api.serve, option.WithBaseURL("https://api.arkhq.io/v1/"), option.WithMaxRetries(0), option.WithCircuitBreaker(option.CircuitBreakerConfig{
+		ConsecutiveFailures: 1,
+		OpenTimeout:         time.Minute,
+		Clock:               clock,
+	}))
+
+	client.Limits.Get(context.Background())
+	clock.Advance(time.Minute)
+	// The probe fails, which opens the breaker for another minute.
+	if _, err := client.Limits.Get(context.Background()); errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected a probe to be sent")
+	}
+	clock.Advance(59 * time.Second)
+	if _, err := client.Limits.Get(context.Background()); !errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected the breaker to be open again, got %v", err)
+	}
+	if n := api.requests["api.arkhq.io/v1/limits"]; n != 2 {
+		t.Fatalf("expected 2 requests, got %d", n)
+	}
+}
+
+func TestCircuitBreakerFailureRatio(t *testing.T) {
+	clock := newFakeClock()
+	api := &switchableAPI{requests: map[string]int{}}
+	client := newTestClient(api.serve, option.WithBaseURL("https://api.arkhq.io/v1/"), option.WithMaxRetries(0), option.WithCircuitBreaker(option.CircuitBreakerConfig{
+		FailureRatio: 0.5,
+		MinRequests:  4,
+		Window:       time.Minute,
+		Clock:        clock,
+	}))
+
+	statuses := []int{http.StatusOK, http.StatusInternalServerError, http.StatusOK}
+	for _, status := range statuses {
+		api.status = status
+		client.Limits.Get(context.Background())
+	}
+	// A new window starts, forgetting the failure.
+	clock.Advance(time.Minute)
+	for _, status := range statuses {
+		api.status = status
+		if _, err := client.Limits.Get(context.Background()); errors.Is(err, option.ErrCircuitOpen) {
+			t.Fatalf("expected the breaker to be closed")
+		}
+	}
+	api.status = http.StatusInternalServerError
+	client.Limits.Get(context.Background())
+	if _, err := client.Limits.Get(context.Background()); !errors.Is(err, option.ErrCircuitOpen) {
+		t.Fatalf("expected half of the requests failing to open the breaker, got %v", err)
+	}
+}
@@ -0,0 +1,230 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/packages/param"
+)
+
+var emailCommands = []command{
+	{"emails", "send", "[flags]", "Send an email, from flags or from an .eml file with -eml", sendEmail},
+	{"emails", "list", "[flags]", "List emails, newest first", listEmails},
+	{"emails", "get", "[flags] <email-id>", "Show an email", getEmail},
+	{"emails", "deliveries", "<email-id>", "Show the delivery attempts of an email", getDeliveries},
+	{"emails", "retry", "<email-id>", "Retry delivering a failed email", retryEmail},
+}
+
+var emailColumns = []column{
+	{"ID", "id"},
+	{"STATUS", "status"},
+	{"FROM", "from"},
+	{"TO", "to"},
+	{"SUBJECT", "subject"},
+	{"TAG", "tag"},
+	{"TIME", "timestampIso"},
+}
+
+func sendEmail(c *cli, args []string) error {
+	fs := c.flags()
+	var to, cc, bcc, attachments listFlag
+	headers, metadata := mapFlag{}, mapFlag{}
+	from := fs.String("from", "", "sender `address`, e.g. \"Name <hello@example.com>\"")
+	fs.Var(&to, "to", "recipient `addresses`; can be repeated")
+	fs.Var(&cc, "cc", "CC `addresses`; can be repeated")
+	fs.Var(&bcc, "bcc", "BCC `addresses`; can be repeated")
+	replyTo := fs.String("reply-to", "", "reply-to `address`")
+	subject := fs.String("subject", "", "`subject` of the email")
+	html := fs.String("html", "", "HTML `body`")
+	htmlFile := fs.String("html-file", "", "read the HTML body from `file`")
+	text := fs.String("text", "", "plain text `body`")
+	textFile := fs.String("text-file", "", "read the plain text body from `file`")
+	tag := fs.String("tag", "", "`tag` for filtering and analytics")
+	fs.Var(headers, "header", "custom `name=value` header; can be repeated")
+	fs.Var(metadata, "metadata", "`key=value` metadata; can be repeated")
+	fs.Var(&attachments, "attach", "attach the `file`; can be repeated")
+	eml := fs.String("eml", "", "send the MIME message in `file`, or - for stdin, instead of building one from flags")
+	idempotencyKey := fs.String("idempotency-key", "", "idempotency `key`, to safely retry the command")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *from == "" || len(to) == 0 {
+		return fmt.Errorf("-from and -to are required")
+	}
+
+	if *eml != "" {
+		msg := c.stdin
+		if *eml != "-" {
+			f, err := os.Open(*eml)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+			msg = f
+		}
+		res, err := c.client.Emails.SendRawReader(c.ctx, *from, to, msg)
+		if err != nil {
+			return err
+		}
+		return c.print(res.RawJSON(), "data", emailColumns)
+	}
+
+	if *subject == "" {
+		return errors.New("-subject is required")
+	}
+	if err := readFlagFile(html, *htmlFile); err != nil {
+		return err
+	}
+	if err := readFlagFile(text, *textFile); err != nil {
+		return err
+	}
+	if *html == "" && *text == "" {
+		return errors.New("a body is required: -html, -html-file, -text or -text-file")
+	}
+	params := ark.EmailSendParams{
+		From:    *from,
+		To:      to,
+		Cc:      cc,
+		Bcc:     bcc,
+		Subject: *subject,
+	}
+	if *html != "" {
+		params.HTML = ark.String(*html)
+	}
+	if *text != "" {
+		params.Text = ark.String(*text)
+	}
+	if *replyTo != "" {
+		params.ReplyTo = ark.String(*replyTo)
+	}
+	if *tag != "" {
+		params.Tag = ark.String(*tag)
+	}
+	if *idempotencyKey != "" {
+		params.IdempotencyKey = ark.String(*idempotencyKey)
+	}
+	if len(headers) > 0 {
+		params.Headers = headers
+	}
+	if len(metadata) > 0 {
+		params.Metadata = metadata
+	}
+	for _, path := range attachments {
+		attachment, err := ark.AttachmentFromFile(path)
+		if err != nil {
+			return err
+		}
+		params.Attachments = append(params.Attachments, attachment)
+	}
+	res, err := c.client.Emails.Send(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", emailColumns)
+}
+
+// readFlagFile sets value to the content of the file at path, if path is not
+// empty.
+func readFlagFile(value *string, path string) error {
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	*value = string(data)
+	return nil
+}
+
+func listEmails(c *cli, args []string) error {
+	fs := c.flags()
+	status := fs.String("status", "", "only list emails with this `status`: pending, sent, softfail, hardfail, bounced or held")
+	tag := fs.String("tag", "", "only list emails with this `tag`")
+	from := fs.String("from", "", "only list emails from this `address`")
+	to := fs.String("to", "", "only list emails to this `address`")
+	after := fs.String("after", "", "only list emails sent after this `time`, in RFC 3339")
+	before := fs.String("before", "", "only list emails sent before this `time`, in RFC 3339")
+	page, perPage, all := pageFlags(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	params := ark.EmailListParams{Status: ark.EmailListParamsStatus(*status)}
+	for _, opt := range []struct {
+		field *param.Opt[string]
+		value string
+	}{{&params.Tag, *tag}, {&params.From, *from}, {&params.To, *to}, {&params.After, *after}, {&params.Before, *before}} {
+		if opt.value != "" {
+			*opt.field = ark.String(opt.value)
+		}
+	}
+	setPage(&params.Page, &params.PerPage, *page, *perPage)
+	if *all {
+		raw, err := collect(c.client.Emails.ListAutoPaging(c.ctx, params))
+		if err != nil {
+			return err
+		}
+		return c.print(raw, "@this", emailColumns)
+	}
+	res, err := c.client.Emails.List(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", emailColumns)
+}
+
+func getEmail(c *cli, args []string) error {
+	fs := c.flags()
+	expand := fs.String("expand", "", "comma-separated `fields` to include: content, headers, attachments, deliveries, activity, full")
+	args, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	var params ark.EmailGetParams
+	if *expand != "" {
+		params.Expand = ark.String(*expand)
+	}
+	res, err := c.client.Emails.Get(c.ctx, args[0], params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", emailColumns)
+}
+
+var deliveryColumns = []column{
+	{"ID", "id"},
+	{"STATUS", "status"},
+	{"CODE", "code"},
+	{"HOST", "remoteHost"},
+	{"OUTPUT", "output"},
+	{"TIME", "timestampIso"},
+}
+
+func getDeliveries(c *cli, args []string) error {
+	args, err := c.parse(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Emails.GetDeliveries(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	if c.output == "json" {
+		return c.print(res.RawJSON(), "data", nil)
+	}
+	return c.print(res.RawJSON(), "data.deliveries", deliveryColumns)
+}
+
+func retryEmail(c *cli, args []string) error {
+	args, err := c.parse(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Emails.Retry(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", []column{{"ID", "id"}, {"MESSAGE", "message"}})
+}
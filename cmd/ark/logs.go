@@ -0,0 +1,196 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/packages/param"
+	"github.com/tidwall/gjson"
+)
+
+var logCommands = []command{
+	{"logs", "list", "[flags]", "List API request logs, newest first", listLogs},
+	{"logs", "get", "<request-id>", "Show an API request log, with its request and response bodies", getLog},
+	{"logs", "tail", "[flags]", "Print API request logs as they are made, until interrupted", tailLogs},
+}
+
+var logColumns = []column{
+	{"TIME", "timestamp"},
+	{"REQUEST ID", "requestId"},
+	{"METHOD", "method"},
+	{"PATH", "path"},
+	{"STATUS", "statusCode"},
+	{"DURATION (MS)", "durationMs"},
+	{"CREDENTIAL", "credential.name"},
+	{"ERROR", "error.message"},
+}
+
+// logFilters adds the flags filtering logs, and returns a function setting
+// them in params.
+func logFilters(fs *flag.FlagSet) func(params *ark.LogListParams) {
+	status := fs.String("status", "", "only list requests with this `status`: success or error")
+	statusCode := fs.Int64("status-code", 0, "only list requests with this HTTP status `code`")
+	endpoint := fs.String("endpoint", "", "only list requests to this `endpoint`, e.g. emails.send")
+	credentialID := fs.String("credential-id", "", "only list requests made with this API credential `ID`")
+	return func(params *ark.LogListParams) {
+		params.Status = ark.LogListParamsStatus(*status)
+		if *statusCode != 0 {
+			params.StatusCode = ark.Int(*statusCode)
+		}
+		if *endpoint != "" {
+			params.Endpoint = ark.String(*endpoint)
+		}
+		if *credentialID != "" {
+			params.CredentialID = ark.String(*credentialID)
+		}
+	}
+}
+
+func listLogs(c *cli, args []string) error {
+	fs := c.flags()
+	filter := logFilters(fs)
+	start := fs.String("start", "", "only list requests made after this `time`, in RFC 3339")
+	end := fs.String("end", "", "only list requests made before this `time`, in RFC 3339")
+	page, perPage, all := pageFlags(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var params ark.LogListParams
+	filter(&params)
+	var err error
+	if params.StartDate, err = timeFlag("start", *start); err != nil {
+		return err
+	}
+	if params.EndDate, err = timeFlag("end", *end); err != nil {
+		return err
+	}
+	setPage(&params.Page, &params.PerPage, *page, *perPage)
+	if *all {
+		raw, err := collect(c.client.Logs.ListAutoPaging(c.ctx, params))
+		if err != nil {
+			return err
+		}
+		return c.print(raw, "@this", logColumns)
+	}
+	res, err := c.client.Logs.List(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", logColumns)
+}
+
+// timeFlag parses the value of a flag of an RFC 3339 time, if it is set.
+func timeFlag(name, value string) (param.Opt[time.Time], error) {
+	if value == "" {
+		return param.Opt[time.Time]{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return param.Opt[time.Time]{}, fmt.Errorf("invalid -%s: %w", name, err)
+	}
+	return ark.Time(t), nil
+}
+
+func getLog(c *cli, args []string) error {
+	args, err := c.parse(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Logs.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", logColumns)
+}
+
+// tailLogs polls the logs of the requests made since the last poll, and prints
+// those it has not printed yet, oldest first. In JSON mode, it prints a log per
+// line.
+func tailLogs(c *cli, args []string) error {
+	fs := c.flags()
+	filter := logFilters(fs)
+	interval := fs.Duration("interval", 2*time.Second, "`interval` between polls")
+	since := fs.Duration("since", 0, "first print the requests made in this `duration` before now")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var params ark.LogListParams
+	filter(&params)
+	params.PerPage = ark.Int(100)
+
+	start := time.Now().Add(-*since)
+	// seen holds the timestamps of the requests printed at or after latest, the
+	// time of the last of them, which the next poll may list again.
+	seen := map[string]time.Time{}
+	var latest time.Time
+	header := true
+	for {
+		params.StartDate = ark.Time(start)
+		pager := c.client.Logs.ListAutoPaging(c.ctx, params)
+		var entries []ark.LogEntry
+		for pager.Next() {
+			entries = append(entries, pager.Current())
+		}
+		if err := pager.Err(); err != nil {
+			if c.ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+
+		var fresh []gjson.Result
+		for i := len(entries) - 1; i >= 0; i-- {
+			entry := entries[i]
+			if _, ok := seen[entry.RequestID]; ok {
+				continue
+			}
+			seen[entry.RequestID] = entry.Timestamp
+			if entry.Timestamp.After(latest) {
+				latest = entry.Timestamp
+			}
+			fresh = append(fresh, gjson.Parse(entry.RawJSON()))
+		}
+		for id, t := range seen {
+			if t.Before(latest) {
+				delete(seen, id)
+			}
+		}
+		if latest.After(start) {
+			start = latest
+		}
+		if err := c.printLogs(fresh, header); err != nil {
+			return err
+		}
+		header = header && len(fresh) == 0
+
+		select {
+		case <-c.ctx.Done():
+			return nil
+		case <-time.After(*interval):
+		}
+	}
+}
+
+func (c *cli) printLogs(entries []gjson.Result, header bool) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	if c.output != "json" {
+		return c.printRows(entries, logColumns, header)
+	}
+	for _, entry := range entries {
+		var line bytes.Buffer
+		if err := json.Compact(&line, []byte(entry.Raw)); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		if _, err := c.stdout.Write(line.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
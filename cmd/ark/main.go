@@ -0,0 +1,263 @@
+// Command ark is a command-line client of the Ark API, built on the Go SDK.
+//
+// It reads the API key and base URL from ARK_API_KEY and ARK_BASE_URL, like
+// [ark.NewClient], unless they are given with the -api-key and -base-url flags.
+//
+// Usage:
+//
+//	ark [-o json|table|csv] <group> <command> [flags] [args]
+//
+// Run "ark help" for the list of commands, and "ark <group> <command> -h" for
+// their flags.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+	"github.com/ArkHQ-io/ark-go/packages/param"
+)
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
+
+// command is a subcommand, like "emails send".
+type command struct {
+	group, name string
+	// args describes the flags and arguments of the command, in its usage.
+	args    string
+	summary string
+	run     func(c *cli, args []string) error
+}
+
+// commands are the commands of the CLI, in the order they are listed.
+var commands []command
+
+func init() {
+	commands = append(commands, emailCommands...)
+	commands = append(commands, logCommands...)
+	commands = append(commands, tenantCommands...)
+	commands = append(commands, domainCommands...)
+	commands = append(commands, suppressionCommands...)
+	commands = append(commands, webhookCommands...)
+	commands = append(commands, usageCommands...)
+}
+
+// cli is the state shared by the commands.
+type cli struct {
+	ctx    context.Context
+	client ark.Client
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+	// output is the output mode, one of "json", "table" and "csv".
+	output string
+	// cmd is the command being run.
+	cmd command
+	// noKey is true if no API key is set.
+	noKey bool
+}
+
+// errUsage is returned by commands called with invalid flags or arguments,
+// after printing their usage.
+var errUsage = errors.New("usage")
+
+// run runs the CLI with the given arguments, and returns its exit code.
+func run(ctx context.Context, args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	global := flag.NewFlagSet("ark", flag.ContinueOnError)
+	global.SetOutput(stderr)
+	global.Usage = func() { usage(stderr) }
+	output := global.String("o", "json", "output `mode`: json, table or csv")
+	apiKey := global.String("api-key", "", "API `key`, instead of ARK_API_KEY")
+	baseURL := global.String("base-url", "", "base `URL` of the API, instead of ARK_BASE_URL")
+	if err := global.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	switch *output {
+	case "json", "table", "csv":
+	default:
+		fmt.Fprintf(stderr, "ark: invalid output mode %q\n", *output)
+		return 2
+	}
+
+	args = global.Args()
+	if len(args) == 0 || args[0] == "help" {
+		usage(stdout)
+		if len(args) == 0 {
+			return 2
+		}
+		return 0
+	}
+	cmd, ok := findCommand(args)
+	if !ok {
+		fmt.Fprintf(stderr, "ark: unknown command %q\n", strings.Join(args[:min(len(args), 2)], " "))
+		usage(stderr)
+		return 2
+	}
+
+	var opts []option.RequestOption
+	if *apiKey != "" {
+		opts = append(opts, option.WithAPIKey(*apiKey))
+	}
+	if *baseURL != "" {
+		opts = append(opts, option.WithBaseURL(*baseURL))
+	}
+	c := &cli{
+		ctx:    ctx,
+		client: ark.NewClient(opts...),
+		stdin:  stdin,
+		stdout: stdout,
+		stderr: stderr,
+		output: *output,
+		cmd:    cmd,
+		noKey:  *apiKey == "" && os.Getenv("ARK_API_KEY") == "",
+	}
+	err := cmd.run(c, args[2:])
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		return 2
+	case errors.Is(err, flag.ErrHelp):
+		return 0
+	}
+	fmt.Fprintf(stderr, "ark: %s\n", err)
+	return 1
+}
+
+func findCommand(args []string) (command, bool) {
+	if len(args) < 2 {
+		return command{}, false
+	}
+	for _, cmd := range commands {
+		if cmd.group == args[0] && cmd.name == args[1] {
+			return cmd, true
+		}
+	}
+	return command{}, false
+}
+
+func usage(w io.Writer) {
+	fmt.Fprint(w, `Usage: ark [-o json|table|csv] [-api-key key] [-base-url URL] <group> <command> [flags] [args]
+
+Commands:
+`)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		fmt.Fprintf(tw, "  %s %s\t%s\n", cmd.group, cmd.name, cmd.summary)
+	}
+	tw.Flush()
+	fmt.Fprint(w, `
+Run "ark <group> <command> -h" for the flags of a command.
+`)
+}
+
+// flags returns the flag set of the command being run.
+func (c *cli) flags() *flag.FlagSet {
+	fs := flag.NewFlagSet("ark "+c.cmd.group+" "+c.cmd.name, flag.ContinueOnError)
+	fs.SetOutput(c.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.stderr, "Usage: ark %s %s %s\n\n%s.\n", c.cmd.group, c.cmd.name, c.cmd.args, c.cmd.summary)
+		var hasFlags bool
+		fs.VisitAll(func(*flag.Flag) { hasFlags = true })
+		if hasFlags {
+			fmt.Fprintln(c.stderr, "\nFlags:")
+			fs.PrintDefaults()
+		}
+	}
+	return fs
+}
+
+// parse parses the flags of the command, which must be followed by exactly
+// nargs arguments, and returns these. It fails if no API key is set, so that
+// the usage of commands can be printed without one.
+func (c *cli) parse(fs *flag.FlagSet, args []string, nargs int) ([]string, error) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return nil, err
+		}
+		return nil, errUsage
+	}
+	if fs.NArg() != nargs {
+		fmt.Fprintf(c.stderr, "ark %s %s: expected %d argument(s), got %d\n", c.cmd.group, c.cmd.name, nargs, fs.NArg())
+		fs.Usage()
+		return nil, errUsage
+	}
+	if c.noKey {
+		return nil, errors.New("ARK_API_KEY is not set")
+	}
+	return fs.Args(), nil
+}
+
+// isSet reports whether the flag with the given name was set.
+func isSet(fs *flag.FlagSet, name string) bool {
+	var set bool
+	fs.Visit(func(f *flag.Flag) { set = set || f.Name == name })
+	return set
+}
+
+// listFlag is a flag which can be repeated, or given a comma-separated list.
+type listFlag []string
+
+func (f *listFlag) String() string { return strings.Join(*f, ",") }
+
+func (f *listFlag) Set(value string) error {
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*f = append(*f, v)
+		}
+	}
+	return nil
+}
+
+// mapFlag is a flag of key=value pairs, which can be repeated.
+type mapFlag map[string]string
+
+func (f mapFlag) String() string {
+	var pairs []string
+	for k, v := range f {
+		pairs = append(pairs, k+"="+v)
+	}
+	return strings.Join(pairs, ",")
+}
+
+func (f mapFlag) Set(value string) error {
+	k, v, ok := strings.Cut(value, "=")
+	if !ok || k == "" {
+		return fmt.Errorf("expected key=value, got %q", value)
+	}
+	f[k] = v
+	return nil
+}
+
+// pageFlags adds the flags of list commands.
+func pageFlags(fs *flag.FlagSet) (page, perPage *int64, all *bool) {
+	page = fs.Int64("page", 0, "`page` to list, from 1")
+	perPage = fs.Int64("per-page", 0, "`number` of items per page")
+	all = fs.Bool("all", false, "list the items of all pages")
+	return
+}
+
+func setPage(pageOpt, perPageOpt *param.Opt[int64], page, perPage int64) {
+	if page > 0 {
+		*pageOpt = ark.Int(page)
+	}
+	if perPage > 0 {
+		*perPageOpt = ark.Int(perPage)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/arktest"
+	"github.com/tidwall/gjson"
+)
+
+// runArk runs the CLI against srv, and returns its output and exit code.
+func runArk(t *testing.T, srv *arktest.Server, args ...string) (string, int) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	args = append([]string{"-api-key", srv.APIKey, "-base-url", srv.URL + "/v1/"}, args...)
+	code := run(context.Background(), args, strings.NewReader(""), &stdout, &stderr)
+	if code != 0 {
+		return stderr.String(), code
+	}
+	return stdout.String(), code
+}
+
+// mustRun runs the CLI against srv, and fails the test if it fails.
+func mustRun(t *testing.T, srv *arktest.Server, args ...string) string {
+	t.Helper()
+	out, code := runArk(t, srv, args...)
+	if code != 0 {
+		t.Fatalf("ark %s exited with %d: %s", strings.Join(args, " "), code, out)
+	}
+	return out
+}
+
+func TestEmails(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+
+	out := mustRun(t, srv, "emails", "send", "-from", "hello@example.com", "-to", "a@example.com,b@example.com", "-subject", "Hello", "-text", "Hi!", "-tag", "welcome", "-metadata", "user=42")
+	id := gjson.Get(out, "id").String()
+	if id == "" {
+		t.Fatalf("expected the ID of the email, got %s", out)
+	}
+	emails := srv.SentEmails()
+	if len(emails) != 1 || len(emails[0].To) != 2 || emails[0].Text != "Hi!" || emails[0].Metadata["user"] != "42" {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+
+	eml := filepath.Join(t.TempDir(), "message.eml")
+	if err := os.WriteFile(eml, []byte("Subject: Raw\r\n\r\nHi!\r\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	mustRun(t, srv, "emails", "send", "-from", "hello@example.com", "-to", "c@example.com", "-eml", eml)
+	if emails := srv.SentEmails(); len(emails) != 2 || !bytes.Contains(emails[1].RawMessage, []byte("Subject: Raw")) {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+
+	// Messages piped to the standard input, which cannot seek, are sent too.
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	go func() {
+		w.WriteString("Subject: Piped\r\n\r\nHi!\r\n")
+		w.Close()
+	}()
+	var stdout, stderr bytes.Buffer
+	args := []string{"-api-key", srv.APIKey, "-base-url", srv.URL + "/v1/", "emails", "send", "-from", "hello@example.com", "-to", "d@example.com", "-eml", "-"}
+	if code := run(context.Background(), args, r, &stdout, &stderr); code != 0 {
+		t.Fatalf("ark emails send -eml - exited with %d: %s", code, stderr.String())
+	}
+	if emails := srv.SentEmails(); len(emails) != 3 || !bytes.Contains(emails[2].RawMessage, []byte("Subject: Piped")) {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+
+	out = mustRun(t, srv, "-o", "table", "emails", "list", "-tag", "welcome")
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || !strings.HasPrefix(lines[0], "ID ") || !strings.Contains(lines[1], id) || !strings.Contains(lines[1], "Hello") {
+		t.Fatalf("unexpected table:\n%s", out)
+	}
+
+	out = mustRun(t, srv, "emails", "list", "-all", "-per-page", "1")
+	if n := gjson.Get(out, "#").Int(); n != 3 {
+		t.Fatalf("expected 3 emails, got %d:\n%s", n, out)
+	}
+
+	out = mustRun(t, srv, "emails", "get", id)
+	if gjson.Get(out, "subject").String() != "Hello" {
+		t.Fatalf("unexpected email %s", out)
+	}
+	mustRun(t, srv, "-o", "table", "emails", "deliveries", id)
+
+	out, code := runArk(t, srv, "emails", "get", "unknown")
+	if code != 1 || !strings.Contains(out, "404") {
+		t.Fatalf("expected the command to fail with a 404, got %d: %s", code, out)
+	}
+}
+
+func TestTenants(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+
+	tenantID := gjson.Get(mustRun(t, srv, "tenants", "create", "-name", "Acme", "-metadata", "plan=pro"), "id").String()
+	out := mustRun(t, srv, "tenants", "update", "-status", "suspended", tenantID)
+	if gjson.Get(out, "status").String() != "suspended" || gjson.Get(out, "metadata.plan").String() != "pro" {
+		t.Fatalf("unexpected tenant %s", out)
+	}
+
+	domainID := gjson.Get(mustRun(t, srv, "domains", "create", "-tenant", tenantID, "example.com"), "id").String()
+	out = mustRun(t, srv, "-o", "table", "domains", "verify", "-tenant", tenantID, domainID)
+	if !strings.Contains(out, "VERIFIED     true") || !strings.Contains(out, "TXT") {
+		t.Fatalf("unexpected domain:\n%s", out)
+	}
+
+	mustRun(t, srv, "suppressions", "create", "-tenant", tenantID, "-reason", "complaint", "bounce@example.com")
+	out = mustRun(t, srv, "-o", "csv", "suppressions", "list", "-tenant", tenantID)
+	if !strings.HasPrefix(out, "ADDRESS,REASON,CREATED\nbounce@example.com,complaint,") {
+		t.Fatalf("unexpected CSV:\n%s", out)
+	}
+	mustRun(t, srv, "suppressions", "delete", "-tenant", tenantID, "bounce@example.com")
+
+	webhookID := gjson.Get(mustRun(t, srv, "webhooks", "create", "-tenant", tenantID, "-name", "Events", "-url", "https://example.com/hook", "-event", "MessageSent"), "id").String()
+	out = mustRun(t, srv, "webhooks", "update", "-tenant", tenantID, "-enabled=false", webhookID)
+	if gjson.Get(out, "enabled").Bool() || gjson.Get(out, "name").String() != "Events" {
+		t.Fatalf("unexpected webhook %s", out)
+	}
+	out = mustRun(t, srv, "webhooks", "test", "-tenant", tenantID, "-event", "MessageSent", webhookID)
+	if !gjson.Get(out, "success").Bool() {
+		t.Fatalf("unexpected test %s", out)
+	}
+	mustRun(t, srv, "webhooks", "delete", "-tenant", tenantID, webhookID)
+
+	out, code := runArk(t, srv, "domains", "list")
+	if code != 1 || !strings.Contains(out, "-tenant is required") {
+		t.Fatalf("expected the command to fail, got %d: %s", code, out)
+	}
+}
+
+func TestUsageExport(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+	mustRun(t, srv, "tenants", "create", "-name", "Acme")
+
+	out := mustRun(t, srv, "-o", "csv", "usage", "export")
+	if !strings.HasPrefix(out, "tenant_id,tenant_name,external_id,status,sent,") || !strings.Contains(out, ",Acme,,active,0,") {
+		t.Fatalf("unexpected CSV:\n%s", out)
+	}
+}
+
+func TestUsage(t *testing.T) {
+	srv := arktest.NewServer()
+	defer srv.Close()
+
+	for _, test := range []struct {
+		args []string
+		code int
+	}{
+		{nil, 2},
+		{[]string{"help"}, 0},
+		{[]string{"emails"}, 2},
+		{[]string{"emails", "unknown"}, 2},
+		{[]string{"emails", "get"}, 2},
+		{[]string{"emails", "get", "-h"}, 0},
+		{[]string{"-o", "yaml", "emails", "list"}, 2},
+	} {
+		if _, code := runArk(t, srv, test.args...); code != test.code {
+			t.Errorf("ark %s exited with %d, expected %d", strings.Join(test.args, " "), code, test.code)
+		}
+	}
+}
+
+func TestLogsTail(t *testing.T) {
+	now := time.Now().UTC()
+	entry := func(id string, age time.Duration) map[string]any {
+		return map[string]any{
+			"requestId":  id,
+			"timestamp":  now.Add(-age),
+			"method":     "POST",
+			"path":       "/v1/emails",
+			"endpoint":   "emails.send",
+			"statusCode": 200,
+			"durationMs": 12,
+		}
+	}
+	// The API lists the new logs, newest first, along with those seen before.
+	// The tail is interrupted while polling after the last page.
+	pages := [][]map[string]any{
+		{entry("req_2", time.Second), entry("req_1", 2*time.Second)},
+		{entry("req_3", 0), entry("req_2", time.Second)},
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var polls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if polls == len(pages) {
+			cancel()
+			return
+		}
+		data := pages[polls]
+		polls++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"success":    true,
+			"data":       data,
+			"page":       1,
+			"perPage":    100,
+			"total":      len(data),
+			"totalPages": 1,
+		})
+	}))
+	defer srv.Close()
+
+	var stdout, stderr bytes.Buffer
+	code := run(ctx, []string{"-api-key", "key", "-base-url", srv.URL, "logs", "tail", "-interval", "1ms"}, nil, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("expected tail to exit with 0, got %d: %s", code, stderr.String())
+	}
+	var ids []string
+	for _, line := range strings.Split(strings.TrimSpace(stdout.String()), "\n") {
+		ids = append(ids, gjson.Get(line, "requestId").String())
+	}
+	if strings.Join(ids, ",") != "req_1,req_2,req_3" {
+		t.Fatalf("expected each log once, oldest first, got %v", ids)
+	}
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/ArkHQ-io/ark-go/packages/pagination"
+	"github.com/tidwall/gjson"
+)
+
+// column is a column of table and CSV output.
+type column struct {
+	header string
+	// path is the gjson path of the value of the column in an item.
+	path string
+}
+
+// print prints the JSON value at path in raw, which is an object or an array
+// of objects, in the output mode. Tables and CSV have the given columns.
+func (c *cli) print(raw, path string, columns []column) error {
+	value := gjson.Get(raw, path)
+	if !value.Exists() {
+		return fmt.Errorf("unexpected response: %s", raw)
+	}
+	if c.output == "json" {
+		var out bytes.Buffer
+		if err := json.Indent(&out, []byte(value.Raw), "", "  "); err != nil {
+			return err
+		}
+		out.WriteByte('\n')
+		_, err := c.stdout.Write(out.Bytes())
+		return err
+	}
+	items := []gjson.Result{value}
+	if value.IsArray() {
+		items = value.Array()
+	} else if c.output == "table" {
+		return c.printObject(value, columns)
+	}
+	return c.printRows(items, columns, true)
+}
+
+// printObject prints the columns of an object as rows of a table.
+func (c *cli) printObject(value gjson.Result, columns []column) error {
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	for _, col := range columns {
+		fmt.Fprintf(tw, "%s\t%s\n", col.header, cell(value.Get(col.path)))
+	}
+	return tw.Flush()
+}
+
+// printRows prints items as the rows of a table or CSV, under a header row if
+// header is true.
+func (c *cli) printRows(items []gjson.Result, columns []column, header bool) error {
+	rows := make([][]string, 0, len(items)+1)
+	if header {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = col.header
+		}
+		rows = append(rows, row)
+	}
+	for _, item := range items {
+		row := make([]string, len(columns))
+		for i, col := range columns {
+			row[i] = cell(item.Get(col.path))
+		}
+		rows = append(rows, row)
+	}
+
+	if c.output == "csv" {
+		w := csv.NewWriter(c.stdout)
+		w.WriteAll(rows)
+		return w.Error()
+	}
+	tw := tabwriter.NewWriter(c.stdout, 0, 0, 2, ' ', 0)
+	for _, row := range rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// cell formats a value in a table or CSV cell. Arrays are comma-separated.
+func cell(value gjson.Result) string {
+	if value.IsArray() {
+		var values []string
+		for _, v := range value.Array() {
+			values = append(values, cell(v))
+		}
+		return strings.Join(values, ",")
+	}
+	return value.String()
+}
+
+// jsonArray joins the raw JSON of items into an array.
+func jsonArray[T interface{ RawJSON() string }](values []T) string {
+	raws := make([]string, len(values))
+	for i, v := range values {
+		raws[i] = v.RawJSON()
+	}
+	return "[" + strings.Join(raws, ",") + "]"
+}
+
+// collect returns the raw JSON array of the items of all pages.
+func collect[T interface{ RawJSON() string }](pager *pagination.PageNumberPaginationAutoPager[T]) (string, error) {
+	var values []T
+	for pager.Next() {
+		values = append(values, pager.Current())
+	}
+	if err := pager.Err(); err != nil {
+		return "", err
+	}
+	return jsonArray(values), nil
+}
@@ -0,0 +1,470 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/tidwall/gjson"
+)
+
+var tenantCommands = []command{
+	{"tenants", "list", "[flags]", "List tenants", listTenants},
+	{"tenants", "get", "<tenant-id>", "Show a tenant", getTenant},
+	{"tenants", "create", "[flags]", "Create a tenant", createTenant},
+	{"tenants", "update", "[flags] <tenant-id>", "Update a tenant", updateTenant},
+	{"tenants", "delete", "<tenant-id>", "Delete a tenant", deleteTenant},
+}
+
+var tenantColumns = []column{
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"STATUS", "status"},
+	{"CREATED", "createdAt"},
+}
+
+func listTenants(c *cli, args []string) error {
+	fs := c.flags()
+	status := fs.String("status", "", "only list tenants with this `status`: active, suspended or archived")
+	page, perPage, all := pageFlags(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	params := ark.TenantListParams{Status: ark.TenantListParamsStatus(*status)}
+	setPage(&params.Page, &params.PerPage, *page, *perPage)
+	if *all {
+		raw, err := collect(c.client.Tenants.ListAutoPaging(c.ctx, params))
+		if err != nil {
+			return err
+		}
+		return c.print(raw, "@this", tenantColumns)
+	}
+	res, err := c.client.Tenants.List(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", tenantColumns)
+}
+
+func getTenant(c *cli, args []string) error {
+	args, err := c.parse(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Get(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", tenantColumns)
+}
+
+func createTenant(c *cli, args []string) error {
+	fs := c.flags()
+	metadata := mapFlag{}
+	name := fs.String("name", "", "`name` of the tenant")
+	fs.Var(metadata, "metadata", "`key=value` metadata; can be repeated")
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	if *name == "" {
+		return errors.New("-name is required")
+	}
+	params := ark.TenantNewParams{Name: *name}
+	for k, v := range metadata {
+		if params.Metadata == nil {
+			params.Metadata = map[string]ark.TenantNewParamsMetadataUnion{}
+		}
+		params.Metadata[k] = ark.TenantNewParamsMetadataUnion{OfString: ark.String(v)}
+	}
+	res, err := c.client.Tenants.New(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", tenantColumns)
+}
+
+func updateTenant(c *cli, args []string) error {
+	fs := c.flags()
+	metadata := mapFlag{}
+	name := fs.String("name", "", "new `name` of the tenant")
+	status := fs.String("status", "", "new `status` of the tenant: active, suspended or archived")
+	fs.Var(metadata, "metadata", "`key=value` metadata, replacing the current metadata; can be repeated")
+	args, err := c.parse(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	params := ark.TenantUpdateParams{Status: ark.TenantUpdateParamsStatus(*status)}
+	if *name != "" {
+		params.Name = ark.String(*name)
+	}
+	for k, v := range metadata {
+		if params.Metadata == nil {
+			params.Metadata = map[string]ark.TenantUpdateParamsMetadataUnion{}
+		}
+		params.Metadata[k] = ark.TenantUpdateParamsMetadataUnion{OfString: ark.String(v)}
+	}
+	res, err := c.client.Tenants.Update(c.ctx, args[0], params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", tenantColumns)
+}
+
+func deleteTenant(c *cli, args []string) error {
+	args, err := c.parse(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Delete(c.ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", messageColumns)
+}
+
+// messageColumns are the columns of the responses of deletions.
+var messageColumns = []column{{"MESSAGE", "message"}}
+
+var domainCommands = []command{
+	{"domains", "list", "-tenant <tenant-id>", "List the sending domains of a tenant", listDomains},
+	{"domains", "get", "-tenant <tenant-id> <domain-id>", "Show a domain, with the DNS records to configure", getDomain},
+	{"domains", "create", "-tenant <tenant-id> <domain>", "Add a sending domain to a tenant", createDomain},
+	{"domains", "delete", "-tenant <tenant-id> <domain-id>", "Delete a domain", deleteDomain},
+	{"domains", "verify", "-tenant <tenant-id> <domain-id>", "Check the DNS records of a domain", verifyDomain},
+}
+
+var domainColumns = []column{
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"VERIFIED", "verified"},
+	{"VERIFIED AT", "verifiedAt"},
+}
+
+// tenantCommand adds the -tenant flag of the commands of the resources of
+// tenants, parses the flags, and returns the tenant ID and the arguments.
+func (c *cli) tenantCommand(fs *flag.FlagSet, args []string, nargs int) (string, []string, error) {
+	tenantID := fs.String("tenant", "", "`ID` of the tenant (required)")
+	args, err := c.parse(fs, args, nargs)
+	if err != nil {
+		return "", nil, err
+	}
+	if *tenantID == "" {
+		return "", nil, errors.New("-tenant is required")
+	}
+	return *tenantID, args, nil
+}
+
+func listDomains(c *cli, args []string) error {
+	tenantID, _, err := c.tenantCommand(c.flags(), args, 0)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Domains.List(c.ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data.domains", domainColumns)
+}
+
+func getDomain(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Domains.Get(c.ctx, args[0], ark.TenantDomainGetParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.printDomain(res.RawJSON())
+}
+
+func createDomain(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Domains.New(c.ctx, tenantID, ark.TenantDomainNewParams{Name: args[0]})
+	if err != nil {
+		return err
+	}
+	return c.printDomain(res.RawJSON())
+}
+
+func deleteDomain(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Domains.Delete(c.ctx, args[0], ark.TenantDomainDeleteParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", messageColumns)
+}
+
+func verifyDomain(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Domains.Verify(c.ctx, args[0], ark.TenantDomainVerifyParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.printDomain(res.RawJSON())
+}
+
+// printDomain prints the domain of a response. Tables list its DNS records
+// after it.
+func (c *cli) printDomain(raw string) error {
+	if err := c.print(raw, "data", domainColumns); err != nil || c.output != "table" {
+		return err
+	}
+	var records []gjson.Result
+	for _, record := range gjson.Get(raw, "data.dnsRecords").Map() {
+		if record.Get("type").Exists() {
+			records = append(records, record)
+		}
+	}
+	if len(records) == 0 {
+		return nil
+	}
+	slices.SortFunc(records, func(a, b gjson.Result) int {
+		return strings.Compare(a.Get("type").String(), b.Get("type").String())
+	})
+	fmt.Fprintln(c.stdout)
+	return c.printRows(records, []column{
+		{"TYPE", "type"},
+		{"NAME", "fullName"},
+		{"VALUE", "value"},
+		{"STATUS", "status"},
+	}, true)
+}
+
+var suppressionCommands = []command{
+	{"suppressions", "list", "-tenant <tenant-id> [flags]", "List the suppressed addresses of a tenant", listSuppressions},
+	{"suppressions", "get", "-tenant <tenant-id> <address>", "Show whether an address is suppressed", getSuppression},
+	{"suppressions", "create", "-tenant <tenant-id> [flags] <address>", "Suppress an address, so that emails are not sent to it", createSuppression},
+	{"suppressions", "delete", "-tenant <tenant-id> <address>", "Remove an address from the suppression list", deleteSuppression},
+}
+
+var suppressionColumns = []column{
+	{"ADDRESS", "address"},
+	{"REASON", "reason"},
+	{"CREATED", "createdAt"},
+}
+
+func listSuppressions(c *cli, args []string) error {
+	fs := c.flags()
+	page, perPage, all := pageFlags(fs)
+	tenantID, _, err := c.tenantCommand(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	var params ark.TenantSuppressionListParams
+	setPage(&params.Page, &params.PerPage, *page, *perPage)
+	if *all {
+		raw, err := collect(c.client.Tenants.Suppressions.ListAutoPaging(c.ctx, tenantID, params))
+		if err != nil {
+			return err
+		}
+		return c.print(raw, "@this", suppressionColumns)
+	}
+	res, err := c.client.Tenants.Suppressions.List(c.ctx, tenantID, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", suppressionColumns)
+}
+
+func getSuppression(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Suppressions.Get(c.ctx, args[0], ark.TenantSuppressionGetParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", append([]column{{"SUPPRESSED", "suppressed"}}, suppressionColumns...))
+}
+
+func createSuppression(c *cli, args []string) error {
+	fs := c.flags()
+	reason := fs.String("reason", "", "`reason` for suppressing the address")
+	tenantID, args, err := c.tenantCommand(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	params := ark.TenantSuppressionNewParams{Address: args[0]}
+	if *reason != "" {
+		params.Reason = ark.String(*reason)
+	}
+	res, err := c.client.Tenants.Suppressions.New(c.ctx, tenantID, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", suppressionColumns)
+}
+
+func deleteSuppression(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Suppressions.Delete(c.ctx, args[0], ark.TenantSuppressionDeleteParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", messageColumns)
+}
+
+var webhookCommands = []command{
+	{"webhooks", "list", "-tenant <tenant-id>", "List the webhooks of a tenant", listWebhooks},
+	{"webhooks", "get", "-tenant <tenant-id> <webhook-id>", "Show a webhook", getWebhook},
+	{"webhooks", "create", "-tenant <tenant-id> [flags]", "Create a webhook", createWebhook},
+	{"webhooks", "update", "-tenant <tenant-id> [flags] <webhook-id>", "Update a webhook", updateWebhook},
+	{"webhooks", "delete", "-tenant <tenant-id> <webhook-id>", "Delete a webhook", deleteWebhook},
+	{"webhooks", "test", "-tenant <tenant-id> -event <event> <webhook-id>", "Send a test event to a webhook", testWebhook},
+}
+
+var webhookColumns = []column{
+	{"ID", "id"},
+	{"NAME", "name"},
+	{"URL", "url"},
+	{"ENABLED", "enabled"},
+	{"EVENTS", "events"},
+}
+
+func listWebhooks(c *cli, args []string) error {
+	tenantID, _, err := c.tenantCommand(c.flags(), args, 0)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Webhooks.List(c.ctx, tenantID)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data.webhooks", webhookColumns)
+}
+
+func getWebhook(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Webhooks.Get(c.ctx, args[0], ark.TenantWebhookGetParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", webhookColumns)
+}
+
+// webhookFlags are the flags of the properties of webhooks.
+type webhookFlags struct {
+	name, url         *string
+	events            listFlag
+	allEvents, enable *bool
+}
+
+func newWebhookFlags(fs *flag.FlagSet) *webhookFlags {
+	f := &webhookFlags{}
+	f.name = fs.String("name", "", "`name` of the webhook")
+	f.url = fs.String("url", "", "`URL` the events are posted to")
+	fs.Var(&f.events, "event", "`event` to subscribe to, e.g. MessageSent; can be repeated")
+	f.allEvents = fs.Bool("all-events", false, "subscribe to all events")
+	f.enable = fs.Bool("enabled", true, "whether the webhook is enabled")
+	return f
+}
+
+func createWebhook(c *cli, args []string) error {
+	fs := c.flags()
+	f := newWebhookFlags(fs)
+	tenantID, _, err := c.tenantCommand(fs, args, 0)
+	if err != nil {
+		return err
+	}
+	if *f.name == "" || *f.url == "" {
+		return errors.New("-name and -url are required")
+	}
+	params := ark.TenantWebhookNewParams{Name: *f.name, URL: *f.url, Events: f.events}
+	if isSet(fs, "all-events") {
+		params.AllEvents = ark.Bool(*f.allEvents)
+	}
+	if isSet(fs, "enabled") {
+		params.Enabled = ark.Bool(*f.enable)
+	}
+	res, err := c.client.Tenants.Webhooks.New(c.ctx, tenantID, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", webhookColumns)
+}
+
+func updateWebhook(c *cli, args []string) error {
+	fs := c.flags()
+	f := newWebhookFlags(fs)
+	tenantID, args, err := c.tenantCommand(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	params := ark.TenantWebhookUpdateParams{TenantID: tenantID, Events: f.events}
+	if isSet(fs, "name") {
+		params.Name = ark.String(*f.name)
+	}
+	if isSet(fs, "url") {
+		params.URL = ark.String(*f.url)
+	}
+	if isSet(fs, "all-events") {
+		params.AllEvents = ark.Bool(*f.allEvents)
+	}
+	if isSet(fs, "enabled") {
+		params.Enabled = ark.Bool(*f.enable)
+	}
+	res, err := c.client.Tenants.Webhooks.Update(c.ctx, args[0], params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", webhookColumns)
+}
+
+func deleteWebhook(c *cli, args []string) error {
+	tenantID, args, err := c.tenantCommand(c.flags(), args, 1)
+	if err != nil {
+		return err
+	}
+	res, err := c.client.Tenants.Webhooks.Delete(c.ctx, args[0], ark.TenantWebhookDeleteParams{TenantID: tenantID})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", messageColumns)
+}
+
+func testWebhook(c *cli, args []string) error {
+	fs := c.flags()
+	event := fs.String("event", "", "`event` to send, e.g. MessageSent (required)")
+	tenantID, args, err := c.tenantCommand(fs, args, 1)
+	if err != nil {
+		return err
+	}
+	if *event == "" {
+		return errors.New("-event is required")
+	}
+	res, err := c.client.Tenants.Webhooks.Test(c.ctx, args[0], ark.TenantWebhookTestParams{
+		TenantID: tenantID,
+		Event:    ark.TenantWebhookTestParamsEvent(*event),
+	})
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", []column{
+		{"EVENT", "event"},
+		{"SUCCESS", "success"},
+		{"STATUS", "statusCode"},
+		{"DURATION (MS)", "duration"},
+		{"ERROR", "error"},
+	})
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"flag"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/packages/param"
+)
+
+var usageCommands = []command{
+	{"usage", "get", "[flags]", "Show the email usage of the account", getUsage},
+	{"usage", "tenants", "[flags]", "List the email usage of each tenant", listTenantUsage},
+	{"usage", "export", "[flags]", "Export the email usage of each tenant, e.g. as CSV with -o csv", exportUsage},
+}
+
+// periodFlags adds the flags of the period of usage reports, and returns a
+// function setting them.
+func periodFlags(fs *flag.FlagSet) func(period, timezone *param.Opt[string]) {
+	p := fs.String("period", "", "`period` to report, e.g. today, this_month, last_30_days or 2024-01")
+	tz := fs.String("timezone", "", "IANA `timezone` of the period, e.g. Europe/Paris")
+	return func(period, timezone *param.Opt[string]) {
+		if *p != "" {
+			*period = ark.String(*p)
+		}
+		if *tz != "" {
+			*timezone = ark.String(*tz)
+		}
+	}
+}
+
+func getUsage(c *cli, args []string) error {
+	fs := c.flags()
+	setPeriod := periodFlags(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var params ark.UsageGetParams
+	setPeriod(&params.Period, &params.Timezone)
+	res, err := c.client.Usage.Get(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", []column{
+		{"PERIOD START", "period.start"},
+		{"PERIOD END", "period.end"},
+		{"SENT", "emails.sent"},
+		{"DELIVERED", "emails.delivered"},
+		{"SOFT FAILED", "emails.soft_failed"},
+		{"HARD FAILED", "emails.hard_failed"},
+		{"BOUNCED", "emails.bounced"},
+		{"HELD", "emails.held"},
+		{"DELIVERY RATE", "rates.delivery_rate"},
+		{"BOUNCE RATE", "rates.bounce_rate"},
+		{"TENANTS", "tenants.total"},
+		{"ACTIVE TENANTS", "tenants.active"},
+	})
+}
+
+// tenantUsageFilters adds the flags filtering the usage of tenants, and returns
+// a function returning their values.
+func tenantUsageFilters(fs *flag.FlagSet) func() (status string, minSent param.Opt[int64]) {
+	status := fs.String("status", "", "only report tenants with this `status`: active, suspended or archived")
+	minSent := fs.Int64("min-sent", 0, "only report tenants which sent at least this `number` of emails")
+	return func() (string, param.Opt[int64]) {
+		if *minSent > 0 {
+			return *status, ark.Int(*minSent)
+		}
+		return *status, param.Opt[int64]{}
+	}
+}
+
+func listTenantUsage(c *cli, args []string) error {
+	fs := c.flags()
+	setPeriod := periodFlags(fs)
+	filters := tenantUsageFilters(fs)
+	sort := fs.String("sort", "", "sort `field`, prefixed with - for descending order: sent, delivered, delivery_rate, bounce_rate or tenant_name")
+	page, perPage, all := pageFlags(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	params := ark.UsageListTenantsParams{Sort: ark.UsageListTenantsParamsSort(*sort)}
+	setPeriod(&params.Period, &params.Timezone)
+	status, minSent := filters()
+	params.Status, params.MinSent = ark.UsageListTenantsParamsStatus(status), minSent
+	setPage(&params.Page, &params.PerPage, *page, *perPage)
+	columns := []column{
+		{"TENANT ID", "tenantId"},
+		{"TENANT", "tenantName"},
+		{"STATUS", "status"},
+		{"SENT", "emails.sent"},
+		{"DELIVERED", "emails.delivered"},
+		{"BOUNCED", "emails.bounced"},
+		{"DELIVERY RATE", "rates.delivery_rate"},
+		{"BOUNCE RATE", "rates.bounce_rate"},
+	}
+	if *all {
+		raw, err := collect(c.client.Usage.ListTenantsAutoPaging(c.ctx, params))
+		if err != nil {
+			return err
+		}
+		return c.print(raw, "@this", columns)
+	}
+	res, err := c.client.Usage.ListTenants(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(res.RawJSON(), "data", columns)
+}
+
+// exportUsage exports the usage of all tenants as JSON, which is printed in the
+// output mode.
+func exportUsage(c *cli, args []string) error {
+	fs := c.flags()
+	setPeriod := periodFlags(fs)
+	filters := tenantUsageFilters(fs)
+	if _, err := c.parse(fs, args, 0); err != nil {
+		return err
+	}
+	var params ark.UsageExportParams
+	setPeriod(&params.Period, &params.Timezone)
+	status, minSent := filters()
+	params.Status, params.MinSent = ark.UsageExportParamsStatus(status), minSent
+	res, err := c.client.Usage.Export(c.ctx, params)
+	if err != nil {
+		return err
+	}
+	return c.print(jsonArray(*res), "@this", []column{
+		{"tenant_id", "tenant_id"},
+		{"tenant_name", "tenant_name"},
+		{"external_id", "external_id"},
+		{"status", "status"},
+		{"sent", "sent"},
+		{"delivered", "delivered"},
+		{"soft_failed", "soft_failed"},
+		{"hard_failed", "hard_failed"},
+		{"bounced", "bounced"},
+		{"held", "held"},
+		{"delivery_rate", "delivery_rate"},
+		{"bounce_rate", "bounce_rate"},
+	})
+}
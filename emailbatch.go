@@ -0,0 +1,210 @@
+package ark
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+	"sync"
+
+	"github.com/ArkHQ-io/ark-go/internal/uuid"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// ErrNotAccepted is reported for emails of a batch which the API did not accept.
+var ErrNotAccepted = errors.New("email was not accepted")
+
+// BatchOptions configures [EmailService.SendBatchChunked].
+type BatchOptions struct {
+	// ChunkSize is the largest number of emails sent in a single request. It
+	// defaults to, and cannot exceed, 100.
+	ChunkSize int
+	// Concurrency is the number of requests sent concurrently. It defaults to 4.
+	Concurrency int
+}
+
+// BatchResult is the result of sending a single email of a batch.
+type BatchResult struct {
+	// Index of the email in [EmailSendBatchParams.Emails].
+	Index int
+	// Messages maps the recipients of the email which were accepted to their
+	// message.
+	Messages map[string]EmailSendBatchResponseDataMessage
+	// Err is set if the request for the chunk containing the email failed, or
+	// wraps [ErrNotAccepted] if not every recipient was accepted.
+	Err error
+}
+
+// BatchError is returned by [EmailService.SendBatchChunked] when some emails were
+// not sent. Use the results to find out which ones.
+type BatchError struct {
+	// Failed is the number of emails with an error, out of Total.
+	Failed int
+	Total  int
+	// Errs are the distinct errors of the failed emails.
+	Errs []error
+}
+
+func (r *BatchError) Error() string {
+	return fmt.Sprintf("%d of %d emails failed, first error: %v", r.Failed, r.Total, r.Errs[0])
+}
+
+func (r *BatchError) Unwrap() []error { return r.Errs }
+
+// SendBatchChunked sends any number of emails, by splitting them into chunks
+// which are sent with [EmailService.SendBatch] concurrently.
+//
+// The results are aligned with params.Emails. To map the messages of a
+// response back to emails, no recipient appears twice in a chunk; a chunk is cut
+// short when it would. The error is nil if every email was accepted, or a
+// [*BatchError] otherwise.
+//
+// Each chunk is sent with its own idempotency key, derived from
+// params.IdempotencyKey and the index of the chunk, so that calling
+// SendBatchChunked again with the same emails and key does not send duplicates.
+// A random key is used if params.IdempotencyKey is omitted.
+func (r *EmailService) SendBatchChunked(ctx context.Context, params EmailSendBatchParams, batch BatchOptions, opts ...option.RequestOption) ([]BatchResult, error) {
+	chunkSize := batch.ChunkSize
+	if chunkSize <= 0 || chunkSize > maxBatchEmails {
+		chunkSize = maxBatchEmails
+	}
+	concurrency := batch.Concurrency
+	if concurrency <= 0 {
+		concurrency = 4
+	}
+	key := params.IdempotencyKey.Value
+	if key == "" {
+		key = "ark-go-" + uuid.New()
+	}
+
+	results := make([]BatchResult, len(params.Emails))
+	for i := range results {
+		results[i].Index = i
+	}
+	chunks := chunkBatch(params.Emails, chunkSize)
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, concurrency)
+send:
+	for i, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			for _, c := range chunks[i:] {
+				for idx := c.start; idx < c.end; idx++ {
+					results[idx].Err = ctx.Err()
+				}
+			}
+			break send
+		}
+		wg.Add(1)
+		go func(i int, chunk batchChunk) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			chunkParams := EmailSendBatchParams{
+				From:           params.From,
+				Emails:         params.Emails[chunk.start:chunk.end],
+				IdempotencyKey: String(fmt.Sprintf("%s-%d", key, i)),
+			}
+			res, err := r.SendBatch(ctx, chunkParams, opts...)
+			for idx := chunk.start; idx < chunk.end; idx++ {
+				if err != nil {
+					results[idx].Err = err
+				} else {
+					results[idx].Messages, results[idx].Err = batchMessages(params.Emails[idx], res.Data.Messages)
+				}
+			}
+		}(i, chunk)
+	}
+	wg.Wait()
+
+	var batchErr BatchError
+	// Errors are told apart by their message, as not all of them are comparable.
+	seen := map[string]bool{}
+	for _, res := range results {
+		if res.Err == nil {
+			continue
+		}
+		batchErr.Failed++
+		if msg := res.Err.Error(); !seen[msg] {
+			seen[msg] = true
+			batchErr.Errs = append(batchErr.Errs, res.Err)
+		}
+	}
+	if batchErr.Failed == 0 {
+		return results, nil
+	}
+	batchErr.Total = len(results)
+	return results, &batchErr
+}
+
+// batchChunk is the range [start, end) of the emails of a batch.
+type batchChunk struct {
+	start, end int
+}
+
+// chunkBatch splits emails into chunks of at most size emails, in which no
+// recipient appears twice.
+func chunkBatch(emails []EmailSendBatchParamsEmail, size int) []batchChunk {
+	var chunks []batchChunk
+	start := 0
+	seen := map[string]bool{}
+	for i, email := range emails {
+		duplicate := false
+		for _, to := range email.To {
+			if seen[recipientKey(to)] {
+				duplicate = true
+				break
+			}
+		}
+		if i > start && (i-start == size || duplicate) {
+			chunks = append(chunks, batchChunk{start, i})
+			start = i
+			clear(seen)
+		}
+		for _, to := range email.To {
+			seen[recipientKey(to)] = true
+		}
+	}
+	if start < len(emails) {
+		chunks = append(chunks, batchChunk{start, len(emails)})
+	}
+	return chunks
+}
+
+// batchMessages returns the messages of the response for the recipients of
+// email, and an error if some of them are missing.
+func batchMessages(email EmailSendBatchParamsEmail, messages map[string]EmailSendBatchResponseDataMessage) (map[string]EmailSendBatchResponseDataMessage, error) {
+	found := make(map[string]EmailSendBatchResponseDataMessage, len(email.To))
+	var missing []string
+	for _, to := range email.To {
+		if msg, ok := messages[to]; ok {
+			found[to] = msg
+			continue
+		}
+		key := recipientKey(to)
+		for recipient, msg := range messages {
+			if recipientKey(recipient) == key {
+				found[to] = msg
+				break
+			}
+		}
+		if _, ok := found[to]; !ok {
+			missing = append(missing, to)
+		}
+	}
+	if len(missing) > 0 {
+		return found, fmt.Errorf("%w: %s", ErrNotAccepted, strings.Join(missing, ", "))
+	}
+	return found, nil
+}
+
+// recipientKey normalizes a recipient to its lower-cased address, without a
+// display name.
+func recipientKey(to string) string {
+	if addr, err := mail.ParseAddress(to); err == nil {
+		to = addr.Address
+	}
+	return strings.ToLower(strings.TrimSpace(to))
+}
@@ -0,0 +1,167 @@
+package ark_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// batchAPI accepts batches, except for recipients at reject.example.com,
+// and fails the requests of chunks containing a recipient at fail.example.com.
+type batchAPI struct {
+	mu     sync.Mutex
+	chunks map[string][]string
+}
+
+func (a *batchAPI) serve(req *http.Request) (*http.Response, error) {
+	var params struct {
+		Emails []struct {
+			To []string `json:"to"`
+		} `json:"emails"`
+	}
+	if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+		return nil, err
+	}
+
+	var recipients []string
+	messages := map[string]any{}
+	status := http.StatusOK
+	for _, email := range params.Emails {
+		for _, to := range email.To {
+			recipients = append(recipients, to)
+			switch {
+			case strings.HasSuffix(to, "@fail.example.com"):
+				status = http.StatusBadRequest
+			case !strings.HasSuffix(to, "@reject.example.com"):
+				messages[strings.ToLower(to)] = map[string]string{"id": "msg_" + to}
+			}
+		}
+	}
+	a.mu.Lock()
+	a.chunks[req.Header.Get("Idempotency-Key")] = recipients
+	a.mu.Unlock()
+
+	body, _ := json.Marshal(map[string]any{"success": status == http.StatusOK, "data": map[string]any{"messages": messages}})
+	return jsonResponse(status, string(body)), nil
+}
+
+func batchEmails(recipients ...string) []ark.EmailSendBatchParamsEmail {
+	emails := make([]ark.EmailSendBatchParamsEmail, len(recipients))
+	for i, to := range recipients {
+		emails[i] = ark.EmailSendBatchParamsEmail{Subject: "Hello", To: []string{to}}
+	}
+	return emails
+}
+
+func TestSendBatchChunked(t *testing.T) {
+	api := &batchAPI{chunks: map[string][]string{}}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	var recipients []string
+	for i := 0; i < 250; i++ {
+		recipients = append(recipients, fmt.Sprintf("user%d@example.com", i))
+	}
+	results, err := client.Emails.SendBatchChunked(context.Background(), ark.EmailSendBatchParams{
+		From:           "hello@yourdomain.com",
+		Emails:         batchEmails(recipients...),
+		IdempotencyKey: ark.String("nightly"),
+	}, ark.BatchOptions{Concurrency: 2})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	sizes := map[string]int{}
+	for key, chunk := range api.chunks {
+		sizes[key] = len(chunk)
+	}
+	if want := map[string]int{"nightly-0": 100, "nightly-1": 100, "nightly-2": 50}; !reflect.DeepEqual(sizes, want) {
+		t.Fatalf("expected chunks %v, got %v", want, sizes)
+	}
+	for i, res := range results {
+		if res.Index != i || res.Err != nil || res.Messages[recipients[i]].ID != "msg_"+recipients[i] {
+			t.Fatalf("unexpected result %d: %+v", i, res)
+		}
+	}
+}
+
+func TestSendBatchChunkedPartialFailure(t *testing.T) {
+	api := &batchAPI{chunks: map[string][]string{}}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	results, err := client.Emails.SendBatchChunked(context.Background(), ark.EmailSendBatchParams{
+		From: "hello@yourdomain.com",
+		Emails: batchEmails(
+			"a@example.com",
+			"User B <B@example.com>",
+			"a@reject.example.com",
+			"A@example.com", // starts a new chunk, as a@example.com is in the first one
+			"b@fail.example.com",
+		),
+		IdempotencyKey: ark.String("key"),
+	}, ark.BatchOptions{ChunkSize: 4})
+
+	if want := map[string][]string{
+		"key-0": {"a@example.com", "User B <B@example.com>", "a@reject.example.com"},
+		"key-1": {"A@example.com", "b@fail.example.com"},
+	}; !reflect.DeepEqual(api.chunks, want) {
+		t.Fatalf("expected chunks %v, got %v", want, api.chunks)
+	}
+
+	var batchErr *ark.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 3 || batchErr.Total != 5 {
+		t.Fatalf("expected 3 of 5 emails to fail, got %v", err)
+	}
+	if !errors.Is(err, ark.ErrNotAccepted) || !errors.Is(err, ark.ErrValidation) {
+		t.Fatalf("expected the errors of the failed emails to be wrapped, got %v", err)
+	}
+
+	if results[0].Err != nil || results[1].Err != nil || results[1].Messages["User B <B@example.com>"].ID != "msg_User B <B@example.com>" {
+		t.Fatalf("expected the first emails to be accepted, got %+v", results[:2])
+	}
+	if !errors.Is(results[2].Err, ark.ErrNotAccepted) {
+		t.Fatalf("expected the rejected email to fail with ErrNotAccepted, got %v", results[2].Err)
+	}
+	for _, res := range results[3:] {
+		var apierr *ark.Error
+		if !errors.As(res.Err, &apierr) || apierr.StatusCode != http.StatusBadRequest {
+			t.Fatalf("expected the emails of the failed chunk to fail with its error, got %v", res.Err)
+		}
+	}
+}
+
+// listError is an error which cannot be compared.
+type listError []string
+
+func (e listError) Error() string { return strings.Join(e, ", ") }
+
+func TestSendBatchChunkedIncomparableError(t *testing.T) {
+	client := ark.NewClient(
+		option.WithAPIKey("My API Key"),
+		option.WithMaxRetries(0),
+		option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+			return nil, listError{"connection", "refused"}
+		}),
+	)
+
+	var recipients []string
+	for i := 0; i < 150; i++ {
+		recipients = append(recipients, fmt.Sprintf("user%d@example.com", i))
+	}
+	_, err := client.Emails.SendBatchChunked(context.Background(), ark.EmailSendBatchParams{
+		From:   "hello@yourdomain.com",
+		Emails: batchEmails(recipients...),
+	}, ark.BatchOptions{})
+	var batchErr *ark.BatchError
+	if !errors.As(err, &batchErr) || batchErr.Failed != 150 || len(batchErr.Errs) != 1 {
+		t.Fatalf("expected 150 emails failing with a single error, got %v", err)
+	}
+}
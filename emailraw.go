@@ -0,0 +1,166 @@
+package ark
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// SendRawReader sends the RFC 2822 MIME message read from message, like
+// [EmailService.SendRaw]. The message is base64-encoded while the request body is
+// being written, so it is never held in memory as a whole.
+//
+// If message implements [io.ReaderAt] and [io.Seeker], like [*os.File] and
+// [*bytes.Reader], the request is retried like any other, reading the message
+// again from the position it had when SendRawReader was called. Otherwise, the
+// request is only attempted once.
+//
+// Messages larger than 14MB fail with [ErrMessageTooLarge], before the request is
+// sent if the size of message can be determined by seeking. Seeking failures,
+// like those of [*os.File] on pipes, are ignored.
+func (r *EmailService) SendRawReader(ctx context.Context, from string, to []string, message io.Reader, opts ...option.RequestOption) (res *EmailSendRawResponse, err error) {
+	body, err := newRawMessageBody(from, to, message)
+	if err != nil {
+		return nil, err
+	}
+	opts = slices.Concat(r.Options, opts, []option.RequestOption{body})
+	path := "emails/raw"
+	err = requestconfig.ExecuteNewRequest(ctx, http.MethodPost, path, nil, &res, opts...)
+	return
+}
+
+// rawMessageBody is a request option which sets the request body to the JSON
+// encoding of [EmailSendRawParams], streaming the message.
+type rawMessageBody struct {
+	prefix, suffix string
+	message        io.Reader
+	// start is the offset of message when the body was created, or -1 if message
+	// cannot seek.
+	start int64
+	// size of message, or -1 if unknown.
+	size int64
+	// at reads the message again for retries, if message is an io.ReaderAt of
+	// known size.
+	at io.ReaderAt
+}
+
+func newRawMessageBody(from string, to []string, message io.Reader) (*rawMessageBody, error) {
+	fromJSON, err := json.Marshal(from)
+	if err != nil {
+		return nil, err
+	}
+	toJSON, err := json.Marshal(to)
+	if err != nil {
+		return nil, err
+	}
+	b := &rawMessageBody{
+		prefix:  fmt.Sprintf(`{"from":%s,"to":%s,"rawMessage":"`, fromJSON, toJSON),
+		suffix:  `"}`,
+		message: message,
+		start:   -1,
+		size:    -1,
+	}
+	s, ok := message.(io.Seeker)
+	if !ok {
+		return b, nil
+	}
+	start, err := s.Seek(0, io.SeekCurrent)
+	if err != nil {
+		// The message is sent once, with an unknown size.
+		return b, nil
+	}
+	end, err := s.Seek(0, io.SeekEnd)
+	if err != nil {
+		return b, nil
+	}
+	if _, err := s.Seek(start, io.SeekStart); err != nil {
+		return nil, err
+	}
+	b.start, b.size = start, end-start
+	if b.size > maxMessageSize {
+		return nil, fmt.Errorf("%w: the message is %d bytes", ErrMessageTooLarge, b.size)
+	}
+	if at, ok := message.(io.ReaderAt); ok {
+		b.at = at
+	}
+	return b, nil
+}
+
+func (b *rawMessageBody) Apply(cfg *requestconfig.RequestConfig) error {
+	cfg.Request.Header.Set("Content-Type", "application/json")
+	cfg.Body = nil
+	if b.size >= 0 {
+		cfg.Request.ContentLength = int64(len(b.prefix)) + int64(base64.StdEncoding.EncodedLen(int(b.size))) + int64(len(b.suffix))
+	}
+	if b.at != nil {
+		cfg.Request.GetBody = b.open
+	} else {
+		cfg.Request.GetBody = nil
+	}
+	var err error
+	cfg.Request.Body, err = b.open()
+	return err
+}
+
+// open returns a reader for the body. Readers of messages which are an
+// io.ReaderAt are independent of each other, so that one can be opened while
+// another is being read, e.g. to log the body.
+func (b *rawMessageBody) open() (io.ReadCloser, error) {
+	message := b.message
+	if b.at != nil {
+		message = io.NewSectionReader(b.at, b.start, b.size)
+	}
+	return io.NopCloser(io.MultiReader(
+		strings.NewReader(b.prefix),
+		&base64Reader{src: io.LimitReader(message, maxMessageSize+1)},
+		strings.NewReader(b.suffix),
+	)), nil
+}
+
+// base64Reader reads src and returns its standard base64 encoding.
+type base64Reader struct {
+	src     io.Reader
+	in      [3 * 1024]byte
+	out     [4 * 1024]byte
+	pending []byte
+	read    int64
+	err     error
+}
+
+func (r *base64Reader) Read(p []byte) (int, error) {
+	for len(r.pending) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+		// Only the last chunk may have a length which is not a multiple of 3, so
+		// that padding is only added at the end.
+		n, err := io.ReadFull(r.src, r.in[:])
+		r.read += int64(n)
+		if r.read > maxMessageSize {
+			r.err = fmt.Errorf("%w: the message is more than %d bytes", ErrMessageTooLarge, maxMessageSize)
+			return 0, r.err
+		}
+		if n > 0 {
+			base64.StdEncoding.Encode(r.out[:], r.in[:n])
+			r.pending = r.out[:base64.StdEncoding.EncodedLen(n)]
+		}
+		switch err {
+		case nil:
+		case io.EOF, io.ErrUnexpectedEOF:
+			r.err = io.EOF
+		default:
+			r.err = err
+		}
+	}
+	n := copy(p, r.pending)
+	r.pending = r.pending[n:]
+	return n, nil
+}
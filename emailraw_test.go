@@ -0,0 +1,223 @@
+package ark_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+const rawMessage = "From: hello@yourdomain.com\r\nTo: user@example.com\r\nSubject: Hello\r\n\r\nHello World"
+
+// rawAPI records the bodies of the requests it receives and fails the first
+// failures requests with a 503.
+type rawAPI struct {
+	failures int
+	bodies   []ark.EmailSendRawParams
+	lengths  []int64
+}
+
+func (a *rawAPI) serve(req *http.Request) (*http.Response, error) {
+	var params ark.EmailSendRawParams
+	if err := json.NewDecoder(req.Body).Decode(&params); err != nil {
+		return nil, err
+	}
+	a.bodies = append(a.bodies, params)
+	a.lengths = append(a.lengths, req.ContentLength)
+	status := http.StatusOK
+	if len(a.bodies) <= a.failures {
+		status = http.StatusServiceUnavailable
+	}
+	return jsonResponse(status, `{"success":true,"data":{"id":"aBc123XyZ"}}`), nil
+}
+
+func TestSendRawReader(t *testing.T) {
+	tests := map[string]struct {
+		message  string
+		reader   func(t *testing.T, s string) io.Reader
+		failures int
+		requests int
+		length   bool
+	}{
+		"seeker": {
+			message:  rawMessage,
+			reader:   func(t *testing.T, s string) io.Reader { return strings.NewReader(s) },
+			failures: 1,
+			requests: 2,
+			length:   true,
+		},
+		"seeker without ReadAt": {
+			message:  rawMessage,
+			reader:   func(t *testing.T, s string) io.Reader { return struct{ io.ReadSeeker }{strings.NewReader(s)} },
+			failures: 1,
+			requests: 1,
+			length:   true,
+		},
+		"reader": {
+			message:  rawMessage,
+			reader:   func(t *testing.T, s string) io.Reader { return io.MultiReader(strings.NewReader(s)) },
+			failures: 1,
+			requests: 1,
+		},
+		"pipe": {
+			message: rawMessage,
+			reader: func(t *testing.T, s string) io.Reader {
+				r, w, err := os.Pipe()
+				if err != nil {
+					t.Fatal(err)
+				}
+				t.Cleanup(func() { r.Close() })
+				go func() {
+					w.WriteString(s)
+					w.Close()
+				}()
+				return r
+			},
+			failures: 1,
+			requests: 1,
+		},
+		"padding": {
+			message:  strings.Repeat("ab", 3*1024+1),
+			reader:   func(t *testing.T, s string) io.Reader { return bytes.NewReader([]byte(s)) },
+			requests: 1,
+			length:   true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			api := &rawAPI{failures: test.failures}
+			client := newTestClient(api.serve)
+			_, err := client.Emails.SendRawReader(
+				context.Background(),
+				"hello@yourdomain.com",
+				[]string{"user@example.com"},
+				test.reader(t, test.message),
+				option.WithMaxRetries(1),
+			)
+			if test.requests > test.failures && err != nil {
+				t.Fatalf("err should be nil: %s", err.Error())
+			}
+			if len(api.bodies) != test.requests {
+				t.Fatalf("expected %d requests, got %d", test.requests, len(api.bodies))
+			}
+			want := base64.StdEncoding.EncodeToString([]byte(test.message))
+			for i, body := range api.bodies {
+				if body.From != "hello@yourdomain.com" || len(body.To) != 1 || body.RawMessage != want {
+					t.Fatalf("unexpected body of request %d: %+v", i, body)
+				}
+				if test.length != (api.lengths[i] > 0) {
+					t.Fatalf("unexpected content length %d", api.lengths[i])
+				}
+			}
+		})
+	}
+}
+
+// TestSendRawReaderLogged checks that logging the request body, which opens it
+// again, does not change the body being sent.
+func TestSendRawReaderLogged(t *testing.T) {
+	message := strings.Repeat("0123456789abcdef", 50<<10/16)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var params ark.EmailSendRawParams
+		if err := json.NewDecoder(r.Body).Decode(&params); err != nil || params.RawMessage != base64.StdEncoding.EncodeToString([]byte(message)) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"success":true,"data":{"id":"aBc123XyZ"}}`))
+	}))
+	defer srv.Close()
+
+	logger := slog.New(slog.NewTextHandler(io.Discard, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	client := ark.NewClient(
+		option.WithAPIKey("My API Key"),
+		option.WithBaseURL(srv.URL),
+		option.WithLogger(logger),
+		option.WithMaxRetries(0),
+	)
+	_, err := client.Emails.SendRawReader(context.Background(), "hello@yourdomain.com", []string{"user@example.com"}, bytes.NewReader([]byte(message)))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+}
+
+func TestSendRawReaderTooLarge(t *testing.T) {
+	api := &rawAPI{}
+	client := newTestClient(api.serve)
+	send := func(r io.Reader) error {
+		_, err := client.Emails.SendRawReader(context.Background(), "hello@yourdomain.com", []string{"user@example.com"}, r)
+		return err
+	}
+
+	if err := send(bytes.NewReader(make([]byte, 14<<20+1))); !errors.Is(err, ark.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+	if len(api.bodies) != 0 {
+		t.Fatalf("expected no request for a message of known size")
+	}
+	if err := send(io.LimitReader(zeros{}, 14<<20+1)); !errors.Is(err, ark.ErrMessageTooLarge) {
+		t.Fatalf("expected ErrMessageTooLarge, got %v", err)
+	}
+}
+
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
+
+// discard reads and discards request bodies.
+func discard(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		io.Copy(io.Discard, req.Body)
+	}
+	return jsonResponse(http.StatusOK, `{"success":true}`), nil
+}
+
+const benchmarkMessageSize = 10 << 20
+
+func BenchmarkSendRaw(b *testing.B) {
+	client := newTestClient(discard)
+	message := bytes.Repeat([]byte("a"), benchmarkMessageSize)
+	b.SetBytes(benchmarkMessageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := client.Emails.SendRaw(context.Background(), ark.EmailSendRawParams{
+			From:       "hello@yourdomain.com",
+			To:         []string{"user@example.com"},
+			RawMessage: base64.StdEncoding.EncodeToString(message),
+		})
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkSendRawReader(b *testing.B) {
+	client := newTestClient(discard)
+	message := bytes.NewReader(bytes.Repeat([]byte("a"), benchmarkMessageSize))
+	b.SetBytes(benchmarkMessageSize)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		message.Seek(0, io.SeekStart)
+		_, err := client.Emails.SendRawReader(context.Background(), "hello@yourdomain.com", []string{"user@example.com"}, message)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
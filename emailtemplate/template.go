@@ -0,0 +1,375 @@
+// Package emailtemplate renders personalized emails from templates, producing
+// params for [ark.EmailService.Send] and [ark.EmailService.SendBatch].
+//
+// The subject and text body are [text/template] templates, and the HTML body is
+// an [html/template] template, so that variables are escaped:
+//
+//	welcome := emailtemplate.Must(emailtemplate.Parse("welcome", emailtemplate.Source{
+//		Subject: "Welcome, {{.name}}",
+//		HTML:    "<p>Hi {{.name}}, your plan is <b>{{.plan}}</b>.</p>",
+//	}))
+//	params, err := welcome.Params("hello@yourdomain.com", []string{"user@example.com"}, emailtemplate.Vars{
+//		"name": "Jane",
+//		"plan": "Pro",
+//	})
+//	if err != nil {
+//		return err
+//	}
+//	res, err := client.Emails.Send(ctx, params)
+//
+// Every variable used by a template must be set, if only to a zero value, or
+// rendering fails with an [*ark.ValidationError] listing the missing variables.
+//
+// [ark.EmailService.Send]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.Send
+// [ark.EmailService.SendBatch]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.SendBatch
+// [*ark.ValidationError]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#ValidationError
+package emailtemplate
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"sort"
+	"strings"
+	"sync"
+	texttemplate "text/template"
+	"text/template/parse"
+
+	"github.com/ArkHQ-io/ark-go"
+)
+
+// Vars are the variables of a template, referenced as {{.name}}.
+type Vars map[string]any
+
+// FuncMap are the functions available to templates, see [text/template.FuncMap].
+type FuncMap map[string]any
+
+// Source is the source of a template. Subject and at least one of HTML and
+// Text are required.
+type Source struct {
+	Subject string
+	HTML    string
+	// Text is the plain text body. If it is empty, it is generated from the
+	// rendered HTML with [TextFromHTML].
+	Text string
+}
+
+// Template is a parsed template. It is safe for concurrent use.
+type Template struct {
+	name    string
+	subject *texttemplate.Template
+	html    *htmltemplate.Template
+	text    *texttemplate.Template
+	// vars maps the variables used by the template to the parts using them.
+	vars map[string][]string
+}
+
+// Parse parses the source of a template.
+func Parse(name string, src Source) (*Template, error) {
+	return parseTemplate(name, src, nil)
+}
+
+// Must panics if err is not nil, and returns t otherwise. It is meant for
+// templates parsed when initializing package variables.
+func Must(t *Template, err error) *Template {
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func parseTemplate(name string, src Source, funcs FuncMap) (*Template, error) {
+	if src.Subject == "" {
+		return nil, fmt.Errorf("emailtemplate: %s: the subject is required", name)
+	}
+	if src.HTML == "" && src.Text == "" {
+		return nil, fmt.Errorf("emailtemplate: %s: one of the html and text bodies is required", name)
+	}
+
+	t := &Template{name: name, vars: map[string][]string{}}
+	var err error
+	t.subject, err = texttemplate.New(name + ".subject").Option("missingkey=error").Funcs(texttemplate.FuncMap(funcs)).Parse(src.Subject)
+	if err != nil {
+		return nil, fmt.Errorf("emailtemplate: %w", err)
+	}
+	t.addVars("subject", t.subject.Tree)
+	if src.HTML != "" {
+		t.html, err = htmltemplate.New(name + ".html").Option("missingkey=error").Funcs(htmltemplate.FuncMap(funcs)).Parse(src.HTML)
+		if err != nil {
+			return nil, fmt.Errorf("emailtemplate: %w", err)
+		}
+		t.addVars("html", t.html.Tree)
+	}
+	if src.Text != "" {
+		t.text, err = texttemplate.New(name + ".text").Option("missingkey=error").Funcs(texttemplate.FuncMap(funcs)).Parse(src.Text)
+		if err != nil {
+			return nil, fmt.Errorf("emailtemplate: %w", err)
+		}
+		t.addVars("text", t.text.Tree)
+	}
+	return t, nil
+}
+
+// Name returns the name of the template.
+func (t *Template) Name() string { return t.name }
+
+// Vars returns the sorted names of the variables used by the template.
+//
+// Only variables referenced directly, like {{.name}} or {{$.name}}, are known.
+// Variables used by templates invoked with {{template}} are only reported
+// missing while rendering.
+func (t *Template) Vars() []string {
+	names := make([]string, 0, len(t.vars))
+	for name := range t.vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Content is a rendered email.
+type Content struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+// Render renders the template with vars. It returns an [*ark.ValidationError]
+// with a field per missing variable, e.g. "vars.name", without rendering if
+// any variable of the template is not set.
+func (t *Template) Render(vars Vars) (Content, error) {
+	if err := t.check("vars", vars); err != nil {
+		return Content{}, err
+	}
+	return t.render(vars)
+}
+
+// Params renders the template with vars, see [Template.Render], and returns
+// params to send the result from from to the to recipients.
+func (t *Template) Params(from string, to []string, vars Vars) (ark.EmailSendParams, error) {
+	c, err := t.Render(vars)
+	if err != nil {
+		return ark.EmailSendParams{}, err
+	}
+	params := ark.EmailSendParams{
+		From:    from,
+		To:      to,
+		Subject: c.Subject,
+	}
+	if c.HTML != "" {
+		params.HTML = ark.String(c.HTML)
+	}
+	if c.Text != "" {
+		params.Text = ark.String(c.Text)
+	}
+	return params, nil
+}
+
+// Recipient is a recipient of a batch, with the variables to render its email.
+type Recipient struct {
+	To   []string
+	Vars Vars
+}
+
+// BatchEmails renders an email per recipient, for [ark.EmailSendBatchParams]
+// or [ark.EmailService.SendBatchChunked].
+//
+// Every recipient is checked before rendering, so that an [*ark.ValidationError]
+// lists the missing variables of all of them, e.g. "recipients[2].vars.name".
+//
+// [ark.EmailService.SendBatchChunked]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.SendBatchChunked
+func (t *Template) BatchEmails(recipients []Recipient) ([]ark.EmailSendBatchParamsEmail, error) {
+	var verr ark.ValidationError
+	for i, r := range recipients {
+		if err := t.check(fmt.Sprintf("recipients[%d].vars", i), r.Vars); err != nil {
+			verr.Fields = append(verr.Fields, err.(*ark.ValidationError).Fields...)
+		}
+	}
+	if len(verr.Fields) > 0 {
+		return nil, &verr
+	}
+
+	emails := make([]ark.EmailSendBatchParamsEmail, len(recipients))
+	for i, r := range recipients {
+		c, err := t.render(r.Vars)
+		if err != nil {
+			return nil, fmt.Errorf("recipients[%d]: %w", i, err)
+		}
+		emails[i] = ark.EmailSendBatchParamsEmail{
+			To:      r.To,
+			Subject: c.Subject,
+		}
+		if c.HTML != "" {
+			emails[i].HTML = ark.String(c.HTML)
+		}
+		if c.Text != "" {
+			emails[i].Text = ark.String(c.Text)
+		}
+	}
+	return emails, nil
+}
+
+// check returns an [*ark.ValidationError] if some variables of the template are
+// not set in vars.
+func (t *Template) check(path string, vars Vars) error {
+	var fields []ark.FieldError
+	for _, name := range t.Vars() {
+		if _, ok := vars[name]; !ok {
+			fields = append(fields, ark.FieldError{
+				Path:   path + "." + name,
+				Reason: fmt.Sprintf("is required by the %s template", strings.Join(t.vars[name], " and ")),
+			})
+		}
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ark.ValidationError{Fields: fields}
+}
+
+func (t *Template) render(vars Vars) (Content, error) {
+	var c Content
+	var buf bytes.Buffer
+	if err := t.subject.Execute(&buf, vars); err != nil {
+		return Content{}, fmt.Errorf("emailtemplate: %w", err)
+	}
+	// Subjects are a single line, so newlines left by the template are removed.
+	c.Subject = strings.Join(strings.Fields(buf.String()), " ")
+	if t.html != nil {
+		buf.Reset()
+		if err := t.html.Execute(&buf, vars); err != nil {
+			return Content{}, fmt.Errorf("emailtemplate: %w", err)
+		}
+		c.HTML = buf.String()
+	}
+	if t.text != nil {
+		buf.Reset()
+		if err := t.text.Execute(&buf, vars); err != nil {
+			return Content{}, fmt.Errorf("emailtemplate: %w", err)
+		}
+		c.Text = buf.String()
+	} else {
+		c.Text = TextFromHTML(c.HTML)
+	}
+	return c, nil
+}
+
+// addVars records the variables referenced by the tree of part.
+func (t *Template) addVars(part string, tree *parse.Tree) {
+	walkVars(tree.Root, true, func(name string) {
+		parts := t.vars[name]
+		if len(parts) == 0 || parts[len(parts)-1] != part {
+			t.vars[name] = append(parts, part)
+		}
+	})
+}
+
+// walkVars calls add for the top-level fields referenced from node. root
+// reports whether dot is the data passed to the template, which is not the case
+// inside {{range}} and {{with}}.
+func walkVars(node parse.Node, root bool, add func(string)) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			walkVars(child, root, add)
+		}
+	case *parse.ActionNode:
+		walkVars(n.Pipe, root, add)
+	case *parse.TemplateNode:
+		walkVars(n.Pipe, root, add)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, cmd := range n.Cmds {
+			walkVars(cmd, root, add)
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			walkVars(arg, root, add)
+		}
+	case *parse.ChainNode:
+		walkVars(n.Node, root, add)
+	case *parse.FieldNode:
+		if root {
+			add(n.Ident[0])
+		}
+	case *parse.VariableNode:
+		if n.Ident[0] == "$" && len(n.Ident) > 1 {
+			add(n.Ident[1])
+		}
+	case *parse.IfNode:
+		walkVars(n.Pipe, root, add)
+		walkVars(n.List, root, add)
+		walkVars(n.ElseList, root, add)
+	case *parse.RangeNode:
+		walkVars(n.Pipe, root, add)
+		walkVars(n.List, false, add)
+		walkVars(n.ElseList, root, add)
+	case *parse.WithNode:
+		walkVars(n.Pipe, root, add)
+		walkVars(n.List, false, add)
+		walkVars(n.ElseList, root, add)
+	}
+}
+
+// Set is a collection of named templates sharing functions. It is safe for
+// concurrent use.
+type Set struct {
+	funcs     FuncMap
+	mu        sync.RWMutex
+	templates map[string]*Template
+}
+
+// NewSet returns an empty set whose templates can call funcs.
+func NewSet(funcs FuncMap) *Set {
+	return &Set{funcs: funcs, templates: map[string]*Template{}}
+}
+
+// Parse parses the source of a template and adds it to the set, replacing any
+// template with the same name.
+func (s *Set) Parse(name string, src Source) (*Template, error) {
+	t, err := parseTemplate(name, src, s.funcs)
+	if err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates[name] = t
+	return t, nil
+}
+
+// ErrNotFound is returned by [Set.Template] for names without a template.
+var ErrNotFound = errors.New("emailtemplate: template not found")
+
+// Lookup returns the template with the given name, or nil if there is none.
+func (s *Set) Lookup(name string) *Template {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.templates[name]
+}
+
+// Template is like [Set.Lookup], but returns an error wrapping [ErrNotFound] if
+// there is no template with the given name.
+func (s *Set) Template(name string) (*Template, error) {
+	if t := s.Lookup(name); t != nil {
+		return t, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrNotFound, name)
+}
+
+// Names returns the sorted names of the templates of the set.
+func (s *Set) Names() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	names := make([]string, 0, len(s.templates))
+	for name := range s.templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,139 @@
+package emailtemplate_test
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/emailtemplate"
+)
+
+func TestParams(t *testing.T) {
+	tmpl := emailtemplate.Must(emailtemplate.Parse("welcome", emailtemplate.Source{
+		Subject: "Welcome, {{.name}}",
+		HTML:    `<p>Hi {{.name}},</p><p>Your plan is <b>{{.plan}}</b>.</p>`,
+	}))
+	if vars := tmpl.Vars(); !reflect.DeepEqual(vars, []string{"name", "plan"}) {
+		t.Fatalf("unexpected vars %v", vars)
+	}
+
+	params, err := tmpl.Params("hello@yourdomain.com", []string{"user@example.com"}, emailtemplate.Vars{
+		"name": "<Jane>",
+		"plan": "Pro",
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if params.From != "hello@yourdomain.com" || !reflect.DeepEqual(params.To, []string{"user@example.com"}) {
+		t.Fatalf("unexpected sender or recipients: %+v", params)
+	}
+	if params.Subject != "Welcome, <Jane>" {
+		t.Fatalf("unexpected subject %q", params.Subject)
+	}
+	if want := `<p>Hi &lt;Jane&gt;,</p><p>Your plan is <b>Pro</b>.</p>`; params.HTML.Value != want {
+		t.Fatalf("expected the html to be escaped, got %q", params.HTML.Value)
+	}
+	if want := "Hi <Jane>,\n\nYour plan is Pro."; params.Text.Value != want {
+		t.Fatalf("expected the text to be generated, got %q", params.Text.Value)
+	}
+}
+
+func TestMissingVars(t *testing.T) {
+	tmpl := emailtemplate.Must(emailtemplate.Parse("receipt", emailtemplate.Source{
+		Subject: "Your receipt from {{$.company}}",
+		HTML:    `{{range .items}}<li>{{.name}}</li>{{end}}`,
+		Text:    `Thanks from {{.company}}{{with .note}}: {{.text}}{{end}}`,
+	}))
+	if vars := tmpl.Vars(); !reflect.DeepEqual(vars, []string{"company", "items", "note"}) {
+		t.Fatalf("expected the fields of range and with not to be vars, got %v", vars)
+	}
+
+	_, err := tmpl.Render(emailtemplate.Vars{"items": nil})
+	var verr *ark.ValidationError
+	if !errors.As(err, &verr) || !errors.Is(err, ark.ErrValidation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	want := []ark.FieldError{
+		{Path: "vars.company", Reason: "is required by the subject and text template"},
+		{Path: "vars.note", Reason: "is required by the text template"},
+	}
+	if !reflect.DeepEqual(verr.Fields, want) {
+		t.Fatalf("expected fields %+v, got %+v", want, verr.Fields)
+	}
+
+	_, err = tmpl.Render(emailtemplate.Vars{"company": "Acme", "items": []map[string]any{{}}, "note": nil})
+	if err == nil || !strings.Contains(err.Error(), `no entry for key "name"`) {
+		t.Fatalf("expected missing nested vars to fail rendering, got %v", err)
+	}
+}
+
+func TestBatchEmails(t *testing.T) {
+	tmpl := emailtemplate.Must(emailtemplate.Parse("reminder", emailtemplate.Source{
+		Subject: "{{.count}} unread messages",
+		Text:    "Hi {{.name}},\nyou have {{.count}} unread messages.",
+	}))
+
+	_, err := tmpl.BatchEmails([]emailtemplate.Recipient{
+		{To: []string{"a@example.com"}, Vars: emailtemplate.Vars{"name": "A", "count": 1}},
+		{To: []string{"b@example.com"}, Vars: emailtemplate.Vars{"name": "B"}},
+		{To: []string{"c@example.com"}, Vars: emailtemplate.Vars{"count": 3}},
+	})
+	var verr *ark.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	want := []ark.FieldError{
+		{Path: "recipients[1].vars.count", Reason: "is required by the subject and text template"},
+		{Path: "recipients[2].vars.name", Reason: "is required by the text template"},
+	}
+	if !reflect.DeepEqual(verr.Fields, want) {
+		t.Fatalf("expected fields %+v, got %+v", want, verr.Fields)
+	}
+
+	emails, err := tmpl.BatchEmails([]emailtemplate.Recipient{
+		{To: []string{"a@example.com"}, Vars: emailtemplate.Vars{"name": "A", "count": 1}},
+		{To: []string{"b@example.com"}, Vars: emailtemplate.Vars{"name": "B", "count": 2}},
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if len(emails) != 2 || emails[1].Subject != "2 unread messages" || emails[1].Text.Value != "Hi B,\nyou have 2 unread messages." || emails[1].HTML.Valid() {
+		t.Fatalf("unexpected emails %+v", emails)
+	}
+	if err := (ark.EmailSendBatchParams{From: "hello@yourdomain.com", Emails: emails}).Validate(); err != nil {
+		t.Fatalf("expected valid batch params, got %v", err)
+	}
+}
+
+func TestSet(t *testing.T) {
+	set := emailtemplate.NewSet(emailtemplate.FuncMap{"upper": strings.ToUpper})
+	if _, err := set.Parse("alert", emailtemplate.Source{Subject: "{{upper .level}}: {{.title}}", Text: "{{.title}}"}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if _, err := set.Parse("invalid", emailtemplate.Source{Subject: "{{.title"}); err == nil {
+		t.Fatalf("expected invalid templates to fail")
+	}
+	if _, err := set.Parse("empty", emailtemplate.Source{Subject: "Hi"}); err == nil {
+		t.Fatalf("expected templates without a body to fail")
+	}
+	if names := set.Names(); !reflect.DeepEqual(names, []string{"alert"}) {
+		t.Fatalf("unexpected names %v", names)
+	}
+
+	tmpl, err := set.Template("alert")
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	c, err := tmpl.Render(emailtemplate.Vars{"level": "warning", "title": "Disk\nfull"})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if c.Subject != "WARNING: Disk full" {
+		t.Fatalf("expected a single-line subject, got %q", c.Subject)
+	}
+	if _, err := set.Template("missing"); !errors.Is(err, emailtemplate.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+}
@@ -0,0 +1,241 @@
+package emailtemplate
+
+import (
+	"html"
+	"strings"
+)
+
+// blockTags are the elements which start on a new line, with the number of
+// newlines separating them from the preceding text.
+var blockTags = map[string]int{
+	"p": 2, "h1": 2, "h2": 2, "h3": 2, "h4": 2, "h5": 2, "h6": 2,
+	"table": 2, "ul": 2, "ol": 2, "blockquote": 2, "pre": 2, "hr": 2,
+	"div": 1, "section": 1, "article": 1, "header": 1, "footer": 1,
+	"tr": 1, "li": 1, "dt": 1, "dd": 1,
+}
+
+// skippedTags are the elements whose content is not displayed.
+var skippedTags = map[string]bool{
+	"head": true, "title": true, "style": true, "script": true, "template": true,
+}
+
+// TextFromHTML returns a plain text version of an HTML document, for clients
+// which do not display HTML. Block elements are separated by newlines, list
+// items are prefixed with "- ", and links are followed by their URL in
+// parentheses.
+func TextFromHTML(src string) string {
+	w := textWriter{}
+	var skip string
+	var pre int
+	var links []textLink
+	for len(src) > 0 {
+		i := strings.IndexByte(src, '<')
+		if i < 0 {
+			i = len(src)
+		}
+		if skip == "" {
+			w.text(html.UnescapeString(src[:i]), pre > 0)
+		}
+		src = src[i:]
+		if src == "" {
+			break
+		}
+
+		if strings.HasPrefix(src, "<!--") {
+			end := strings.Index(src, "-->")
+			if end < 0 {
+				break
+			}
+			src = src[end+len("-->"):]
+			continue
+		}
+		end := tagEnd(src)
+		if end < 0 {
+			// A lone "<" is text.
+			if skip == "" {
+				w.text("<", pre > 0)
+			}
+			src = src[1:]
+			continue
+		}
+		name, closing, attrs := parseTag(src[1:end])
+		src = src[end+1:]
+
+		if skip != "" {
+			if closing && name == skip {
+				skip = ""
+			}
+			continue
+		}
+		switch {
+		case skippedTags[name] && !closing:
+			skip = name
+		case name == "br":
+			w.newline(1)
+		case name == "li" && !closing:
+			w.newline(1)
+			w.raw("- ")
+		case name == "a" && !closing:
+			links = append(links, textLink{href: attrValue(attrs, "href"), start: w.b.Len()})
+		case name == "a" && len(links) > 0:
+			link := links[len(links)-1]
+			links = links[:len(links)-1]
+			text := strings.TrimSpace(w.b.String()[link.start:])
+			href := link.href
+			if href != "" && !strings.HasPrefix(href, "#") && href != text && strings.TrimPrefix(href, "mailto:") != text {
+				w.text(" ("+href+")", false)
+			}
+		case name == "pre":
+			if closing {
+				pre--
+			} else {
+				pre++
+			}
+			w.newline(blockTags[name])
+		case blockTags[name] > 0:
+			w.newline(blockTags[name])
+		}
+	}
+	return strings.TrimSpace(w.b.String())
+}
+
+type textLink struct {
+	href  string
+	start int
+}
+
+// textWriter writes text, collapsing whitespace like browsers do.
+type textWriter struct {
+	b strings.Builder
+	// space is set when whitespace was skipped after the last text.
+	space bool
+	// newlines is the number of newlines ending the text.
+	newlines int
+}
+
+func (w *textWriter) text(s string, pre bool) {
+	if pre {
+		w.raw(s)
+		return
+	}
+	if s == "" {
+		return
+	}
+	if isSpace(rune(s[0])) {
+		w.space = true
+	}
+	for i, field := range strings.FieldsFunc(s, isSpace) {
+		if (i > 0 || w.space) && w.b.Len() > 0 && w.newlines == 0 {
+			w.b.WriteByte(' ')
+		}
+		w.raw(strings.ReplaceAll(field, "\u00a0", " "))
+		w.space = false
+	}
+	if isSpace(rune(s[len(s)-1])) {
+		w.space = true
+	}
+}
+
+// raw writes s as is.
+func (w *textWriter) raw(s string) {
+	if s == "" {
+		return
+	}
+	w.b.WriteString(s)
+	if trimmed := strings.TrimRight(s, "\n"); trimmed == "" {
+		w.newlines += len(s)
+	} else {
+		w.newlines = len(s) - len(trimmed)
+	}
+}
+
+// newline ends the current line, leaving n newlines after the text.
+func (w *textWriter) newline(n int) {
+	if w.b.Len() == 0 {
+		return
+	}
+	for w.newlines < n {
+		w.b.WriteByte('\n')
+		w.newlines++
+	}
+	w.space = false
+}
+
+func isSpace(r rune) bool {
+	switch r {
+	case ' ', '\t', '\n', '\r', '\f':
+		return true
+	}
+	return false
+}
+
+// tagEnd returns the index of the ">" ending the tag at the start of s, or -1
+// if s does not start with a tag.
+func tagEnd(s string) int {
+	if len(s) < 2 || !(s[1] == '/' || s[1] == '!' || 'a' <= s[1]|0x20 && s[1]|0x20 <= 'z') {
+		return -1
+	}
+	var quote byte
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '>':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseTag parses the content of a tag, between "<" and ">".
+func parseTag(s string) (name string, closing bool, attrs string) {
+	if strings.HasPrefix(s, "/") {
+		closing = true
+		s = s[1:]
+	}
+	end := strings.IndexFunc(s, func(r rune) bool { return isSpace(r) || r == '/' })
+	if end < 0 {
+		end = len(s)
+	}
+	return strings.ToLower(s[:end]), closing, s[end:]
+}
+
+// attrValue returns the unescaped value of the named attribute.
+func attrValue(attrs, name string) string {
+	for {
+		attrs = strings.TrimLeftFunc(attrs, func(r rune) bool { return isSpace(r) || r == '/' })
+		if attrs == "" {
+			return ""
+		}
+		end := strings.IndexFunc(attrs, func(r rune) bool { return isSpace(r) || r == '=' })
+		if end < 0 {
+			end = len(attrs)
+		}
+		key := strings.ToLower(attrs[:end])
+		attrs = strings.TrimLeftFunc(attrs[end:], isSpace)
+		value := ""
+		if strings.HasPrefix(attrs, "=") {
+			attrs = strings.TrimLeftFunc(attrs[1:], isSpace)
+			if attrs != "" && (attrs[0] == '"' || attrs[0] == '\'') {
+				end := strings.IndexByte(attrs[1:], attrs[0])
+				if end < 0 {
+					end = len(attrs) - 1
+				}
+				value, attrs = attrs[1:1+end], attrs[min(len(attrs), end+2):]
+			} else {
+				end := strings.IndexFunc(attrs, isSpace)
+				if end < 0 {
+					end = len(attrs)
+				}
+				value, attrs = attrs[:end], attrs[end:]
+			}
+		}
+		if key == name {
+			return html.UnescapeString(value)
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package emailtemplate_test
+
+import (
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go/emailtemplate"
+)
+
+func TestTextFromHTML(t *testing.T) {
+	tests := map[string]struct {
+		html string
+		text string
+	}{
+		"whitespace": {
+			html: "<p>Hello,\n   <b>world</b> !</p>",
+			text: "Hello, world !",
+		},
+		"blocks": {
+			html: "<h1>Title</h1><div>First</div><div>Second<br>line</div><p>Para</p>",
+			text: "Title\n\nFirst\nSecond\nline\n\nPara",
+		},
+		"lists": {
+			html: "<p>Items:</p><ul>\n<li>One</li>\n<li>Two</li>\n</ul>",
+			text: "Items:\n\n- One\n- Two",
+		},
+		"links": {
+			html: `<a href="https://example.com/a?x=1&amp;y=2">Open</a> <a href="https://example.com">https://example.com</a> <a href='mailto:a@example.com'>a@example.com</a> <a href="#top">Top</a>`,
+			text: "Open (https://example.com/a?x=1&y=2) https://example.com a@example.com Top",
+		},
+		"skipped": {
+			html: "<html><head><title>T</title><style>p { color: red }</style></head><body><!-- hidden --><p>Body</p><script>alert(1)</script></body></html>",
+			text: "Body",
+		},
+		"entities": {
+			html: "Fish &amp; chips&nbsp;&nbsp;for 2 &lt; 3 people",
+			text: "Fish & chips  for 2 < 3 people",
+		},
+		"pre": {
+			html: "<p>Code:</p><pre>a  b\n  c</pre><p>End</p>",
+			text: "Code:\n\na  b\n  c\n\nEnd",
+		},
+		"lone angle bracket": {
+			html: "1 < 2 and 3 > 2",
+			text: "1 < 2 and 3 > 2",
+		},
+	}
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			if text := emailtemplate.TextFromHTML(test.html); text != test.text {
+				t.Fatalf("expected %q, got %q", test.text, text)
+			}
+		})
+	}
+}
@@ -0,0 +1,29 @@
+package ark
+
+import "github.com/ArkHQ-io/ark-go/internal/apierror"
+
+// Sentinel errors which an [*Error] matches with [errors.Is], depending on its
+// status code:
+//
+//	if errors.Is(err, ark.ErrNotFound) {
+//		// ...
+//	}
+var (
+	// ErrValidation is matched by 400 Bad Request and 422 Unprocessable Entity
+	// responses.
+	ErrValidation = apierror.ErrValidation
+	// ErrUnauthorized is matched by 401 Unauthorized responses.
+	ErrUnauthorized = apierror.ErrUnauthorized
+	// ErrNotFound is matched by 404 Not Found responses.
+	ErrNotFound = apierror.ErrNotFound
+	// ErrRateLimited is matched by 429 Too Many Requests responses.
+	ErrRateLimited = apierror.ErrRateLimited
+)
+
+// ValidationError lists the request fields which were rejected, either by the
+// API or by client-side validation. Use [errors.As] to get it from an error
+// returned by the client.
+type ValidationError = apierror.ValidationError
+
+// FieldError describes why the value of a single request field was rejected.
+type FieldError = apierror.FieldError
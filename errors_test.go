@@ -0,0 +1,54 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusNotFound, `{"success":false,"error":{"code":"not_found","message":"Email not found"},"meta":{"requestId":"req_abc123"}}`), nil
+	})
+	_, err := client.Emails.Get(context.Background(), "aBc123XyZ", ark.EmailGetParams{})
+	if !errors.Is(err, ark.ErrNotFound) {
+		t.Fatalf("expected ErrNotFound, got %v", err)
+	}
+	if errors.Is(err, ark.ErrRateLimited) {
+		t.Fatalf("did not expect ErrRateLimited")
+	}
+
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) {
+		t.Fatalf("expected an *ark.Error, got %T", err)
+	}
+	if apierr.Code != "not_found" || apierr.Message != "Email not found" || apierr.RequestID != "req_abc123" {
+		t.Fatalf("unexpected error fields %+v", apierr)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusUnprocessableEntity, `{"success":false,"error":{"code":"validation_error","message":"Invalid request","details":{"metadata":{"order-id":["invalid key"]}}}}`), nil
+	}, option.WithMaxRetries(0))
+	_, err := client.Emails.Send(context.Background(), ark.EmailSendParams{
+		From:     "hello@yourdomain.com",
+		Subject:  "Hello World",
+		To:       []string{"user@example.com"},
+		Metadata: map[string]string{"order-id": "123"},
+	})
+
+	var verr *ark.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	want := []ark.FieldError{{Path: "metadata.order-id", Reason: "invalid key"}}
+	if !reflect.DeepEqual(verr.Fields, want) {
+		t.Fatalf("expected %+v, got %+v", want, verr.Fields)
+	}
+}
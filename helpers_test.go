@@ -0,0 +1,73 @@
+package ark_test
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// newTestClient returns a client whose requests are answered by serve instead of
+// being sent over the network. The given options are applied after the defaults.
+func newTestClient(serve func(req *http.Request) (*http.Response, error), opts ...option.RequestOption) ark.Client {
+	return ark.NewClient(append([]option.RequestOption{
+		option.WithAPIKey("My API Key"),
+		option.WithHTTPClient(&http.Client{Transport: &closureTransport{fn: serve}}),
+	}, opts...)...)
+}
+
+// jsonResponse returns a response with the given status and JSON body.
+func jsonResponse(status int, body string) *http.Response {
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}
+}
+
+// fakeClock is an option.Clock whose waits return immediately, advancing the
+// time by their duration unless the clock is frozen.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	frozen bool
+	waits  []time.Duration
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.waits = append(c.waits, d)
+	if !c.frozen {
+		c.now = c.now.Add(d)
+	}
+	ch := make(chan time.Time, 1)
+	ch <- c.now
+	return ch
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+}
+
+func (c *fakeClock) Waits() []time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.waits
+}
@@ -0,0 +1,98 @@
+package ark_test
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// flakyKeys answers two of every three attempts with a 503, recording the
+// idempotency key of every attempt.
+func flakyKeys(keys *[]string) func(req *http.Request) (*http.Response, error) {
+	attempts := 0
+	return func(req *http.Request) (*http.Response, error) {
+		*keys = append(*keys, req.Header.Get("Idempotency-Key"))
+		attempts++
+		res := jsonResponse(http.StatusOK, `{}`)
+		if attempts%3 != 0 {
+			res.StatusCode = http.StatusServiceUnavailable
+		}
+		res.Header.Set("Retry-After-Ms", "1")
+		return res, nil
+	}
+}
+
+var sendParams = ark.EmailSendParams{
+	From:    "hello@yourdomain.com",
+	Subject: "Hello",
+	To:      []string{"user@example.com"},
+	Text:    ark.String("Hello"),
+}
+
+func TestIdempotencyKeys(t *testing.T) {
+	var keys []string
+	client := newTestClient(flakyKeys(&keys), option.WithMaxRetries(2), option.WithIdempotencyKeys())
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.Emails.Send(context.Background(), sendParams); err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+	}
+	if len(keys) != 6 {
+		t.Fatalf("expected 6 attempts, got %d", len(keys))
+	}
+	if !strings.HasPrefix(keys[0], "ark-go-") || keys[0] != keys[1] || keys[1] != keys[2] {
+		t.Fatalf("expected every attempt of a call to reuse its key, got %v", keys[:3])
+	}
+	if keys[3] == keys[0] || keys[3] != keys[5] {
+		t.Fatalf("expected each call to have its own key, got %v", keys)
+	}
+
+	keys = nil
+	params := sendParams
+	params.IdempotencyKey = ark.String("my-key")
+	if _, err := client.Emails.Send(context.Background(), params); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if keys[0] != "my-key" || keys[2] != "my-key" {
+		t.Fatalf("expected the key of the params to be used, got %v", keys)
+	}
+
+	keys = nil
+	_, err := client.Emails.SendRaw(context.Background(), ark.EmailSendRawParams{
+		From:       "hello@yourdomain.com",
+		To:         []string{"user@example.com"},
+		RawMessage: "U3ViamVjdDogSGkNCg0KSGk=",
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if keys[0] != "" {
+		t.Fatalf("expected no key for requests which do not support one, got %q", keys[0])
+	}
+}
+
+type jobKey struct{}
+
+func TestIdempotencyKeyGenerator(t *testing.T) {
+	var keys []string
+	client := newTestClient(flakyKeys(&keys), option.WithMaxRetries(2), option.WithIdempotencyKeyGenerator(func(ctx context.Context) string {
+		return ctx.Value(jobKey{}).(string)
+	}))
+	ctx := context.WithValue(context.Background(), jobKey{}, "job-42")
+	if _, err := client.Emails.SendBatch(ctx, ark.EmailSendBatchParams{
+		From:   "hello@yourdomain.com",
+		Emails: []ark.EmailSendBatchParamsEmail{{Subject: "Hello", To: []string{"user@example.com"}}},
+	}); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	for _, key := range keys {
+		if key != "job-42" {
+			t.Fatalf("expected the generated key, got %v", keys)
+		}
+	}
+}
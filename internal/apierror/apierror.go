@@ -3,6 +3,8 @@
 package apierror
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httputil"
@@ -11,10 +13,34 @@ import (
 	"github.com/ArkHQ-io/ark-go/packages/respjson"
 )
 
+// Sentinel errors which an [*Error] matches with [errors.Is], depending on its
+// status code.
+var (
+	// ErrValidation is matched by 400 Bad Request and 422 Unprocessable Entity
+	// responses.
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthorized is matched by 401 Unauthorized responses.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrNotFound is matched by 404 Not Found responses.
+	ErrNotFound = errors.New("not found")
+	// ErrRateLimited is matched by 429 Too Many Requests responses.
+	ErrRateLimited = errors.New("rate limited")
+)
+
 // Error represents an error that originates from the API, i.e. when a request is
 // made and the API returns a response with a HTTP status code. Other errors are
 // not wrapped by this SDK.
 type Error struct {
+	// Machine-readable error code from the error envelope, e.g.
+	// "validation_error".
+	Code string `json:"-"`
+	// Human-readable error message from the error envelope.
+	Message string `json:"-"`
+	// Additional details about the error, if any. The shape depends on Code.
+	Details any `json:"-"`
+	// Unique request identifier for debugging and support, from the meta of the
+	// error envelope.
+	RequestID string `json:"-"`
 	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
 	JSON struct {
 		ExtraFields map[string]respjson.Field
@@ -25,10 +51,50 @@ type Error struct {
 	Response   *http.Response
 }
 
+// envelope is the body of an error response from the API.
+type envelope struct {
+	Error struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+		Details any    `json:"details"`
+	} `json:"error"`
+	Meta struct {
+		RequestID string `json:"requestId"`
+	} `json:"meta"`
+}
+
 // Returns the unmodified JSON received from the API
 func (r Error) RawJSON() string { return r.JSON.raw }
 func (r *Error) UnmarshalJSON(data []byte) error {
-	return apijson.UnmarshalRoot(data, r)
+	if err := apijson.UnmarshalRoot(data, r); err != nil {
+		return err
+	}
+	// The body isn't required to be an error envelope, e.g. when the error
+	// comes from a proxy, so failing to decode one is not an error.
+	var env envelope
+	if json.Unmarshal(data, &env) == nil {
+		r.Code = env.Error.Code
+		r.Message = env.Error.Message
+		r.Details = env.Error.Details
+		r.RequestID = env.Meta.RequestID
+	}
+	return nil
+}
+
+// Is reports whether target is the sentinel error for the status code of r,
+// e.g. [ErrNotFound] for a 404 Not Found response.
+func (r *Error) Is(target error) bool {
+	switch target {
+	case ErrValidation:
+		return r.StatusCode == http.StatusBadRequest || r.StatusCode == http.StatusUnprocessableEntity
+	case ErrUnauthorized:
+		return r.StatusCode == http.StatusUnauthorized
+	case ErrNotFound:
+		return r.StatusCode == http.StatusNotFound
+	case ErrRateLimited:
+		return r.StatusCode == http.StatusTooManyRequests
+	}
+	return false
 }
 
 func (r *Error) Error() string {
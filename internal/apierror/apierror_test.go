@@ -0,0 +1,64 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	var aerr Error
+	err := aerr.UnmarshalJSON([]byte(`{
+		"success": false,
+		"error": {
+			"code": "validation_error",
+			"message": "Request validation failed",
+			"details": {"from": ["Domain is not verified"]}
+		},
+		"meta": {"requestId": "req_abc123"}
+	}`))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	if aerr.Code != "validation_error" || aerr.Message != "Request validation failed" || aerr.RequestID != "req_abc123" {
+		t.Fatalf("unexpected error fields %+v", aerr)
+	}
+	details, ok := aerr.Details.(map[string]any)
+	if !ok || details["from"] == nil {
+		t.Fatalf("unexpected details %#v", aerr.Details)
+	}
+}
+
+func TestErrorNotAnEnvelope(t *testing.T) {
+	for _, body := range []string{`<html>Bad Gateway</html>`, `{"error": "oops"}`, ``} {
+		var aerr Error
+		if err := aerr.UnmarshalJSON([]byte(body)); err != nil {
+			t.Fatalf("err should be nil for %q: %s", body, err)
+		}
+		if aerr.Code != "" || aerr.Message != "" || aerr.RequestID != "" {
+			t.Fatalf("expected no envelope fields for %q, got %+v", body, aerr)
+		}
+	}
+}
+
+func TestErrorIs(t *testing.T) {
+	tests := map[int]error{
+		http.StatusBadRequest:          ErrValidation,
+		http.StatusUnprocessableEntity: ErrValidation,
+		http.StatusUnauthorized:        ErrUnauthorized,
+		http.StatusNotFound:            ErrNotFound,
+		http.StatusTooManyRequests:     ErrRateLimited,
+		http.StatusInternalServerError: nil,
+	}
+	sentinels := []error{ErrValidation, ErrUnauthorized, ErrNotFound, ErrRateLimited}
+
+	for status, want := range tests {
+		err := fmt.Errorf("wrapped: %w", &Error{StatusCode: status})
+		for _, sentinel := range sentinels {
+			if got := errors.Is(err, sentinel); got != (sentinel == want) {
+				t.Errorf("errors.Is(%d, %v) = %v", status, sentinel, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,158 @@
+package apierror
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FieldError describes why the value of a single request field was rejected.
+type FieldError struct {
+	// Path of the field in the request body, e.g. "metadata.order-id" or "to[3]".
+	Path   string
+	Reason string
+}
+
+// ValidationError lists the request fields which were rejected, either by the
+// API or by client-side validation. It matches [ErrValidation] with
+// [errors.Is].
+type ValidationError struct {
+	// Err is the error returned by the API, or nil if the request was rejected
+	// before it was sent.
+	Err    *Error
+	Fields []FieldError
+}
+
+func (r *ValidationError) Error() string {
+	var b strings.Builder
+	b.WriteString("validation failed")
+	if r.Err != nil && r.Err.Message != "" {
+		b.WriteString(": ")
+		b.WriteString(r.Err.Message)
+	}
+	for i, f := range r.Fields {
+		if i == 0 {
+			b.WriteString(": ")
+		} else {
+			b.WriteString("; ")
+		}
+		if f.Path != "" {
+			b.WriteString(f.Path)
+			b.WriteString(": ")
+		}
+		b.WriteString(f.Reason)
+	}
+	return b.String()
+}
+
+func (r *ValidationError) Unwrap() error {
+	if r.Err == nil {
+		return ErrValidation
+	}
+	return r.Err
+}
+
+// ValidationError returns the fields rejected by the API, or nil if r is not a
+// validation error, see [ErrValidation]. It is also used by [errors.As]:
+//
+//	var verr *ark.ValidationError
+//	if errors.As(err, &verr) {
+//		for _, f := range verr.Fields {
+//			// ...
+//		}
+//	}
+//
+// The fields are read from the details of the error envelope, which can either
+// map field paths to one or more reasons, nesting objects for nested fields, or
+// list objects with a "field" or "path" and a "message" or "reason". Fields may
+// be empty, in which case [Error.Message] describes the problem.
+func (r *Error) ValidationError() *ValidationError {
+	if !r.Is(ErrValidation) {
+		return nil
+	}
+	verr := &ValidationError{Err: r}
+	collectFieldErrors(&verr.Fields, "", r.Details)
+	sort.SliceStable(verr.Fields, func(i, j int) bool {
+		return verr.Fields[i].Path < verr.Fields[j].Path
+	})
+	return verr
+}
+
+func (r *Error) As(target any) bool {
+	if t, ok := target.(**ValidationError); ok {
+		if verr := r.ValidationError(); verr != nil {
+			*t = verr
+			return true
+		}
+	}
+	return false
+}
+
+func collectFieldErrors(fields *[]FieldError, path string, details any) {
+	switch v := details.(type) {
+	case string:
+		*fields = append(*fields, FieldError{Path: path, Reason: v})
+	case map[string]any:
+		if reason, ok := fieldReason(v); ok {
+			*fields = append(*fields, FieldError{Path: fieldPath(v, path), Reason: reason})
+			return
+		}
+		for key, value := range v {
+			collectFieldErrors(fields, joinPath(path, key), value)
+		}
+	case []any:
+		for i, value := range v {
+			// Reasons and field error objects apply to path itself, anything else
+			// to the element at index i.
+			elemPath := path + "[" + strconv.Itoa(i) + "]"
+			switch value := value.(type) {
+			case string:
+				elemPath = path
+			case map[string]any:
+				if _, ok := fieldReason(value); ok {
+					elemPath = path
+				}
+			}
+			collectFieldErrors(fields, elemPath, value)
+		}
+	}
+}
+
+// fieldReason returns the reason if m describes a single field error, like
+// {"field": "to[3]", "message": "is not a valid email address"}.
+func fieldReason(m map[string]any) (string, bool) {
+	for _, key := range []string{"message", "reason"} {
+		if reason, ok := m[key].(string); ok {
+			return reason, true
+		}
+	}
+	return "", false
+}
+
+func fieldPath(m map[string]any, parent string) string {
+	for _, key := range []string{"field", "path"} {
+		switch p := m[key].(type) {
+		case string:
+			return joinPath(parent, p)
+		case []any:
+			path := parent
+			for _, elem := range p {
+				path = joinPath(path, fmt.Sprint(elem))
+			}
+			return path
+		}
+	}
+	return parent
+}
+
+// joinPath appends key to path, using an index for numeric keys.
+func joinPath(path, key string) string {
+	if _, err := strconv.Atoi(key); err == nil && path != "" {
+		return path + "[" + key + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,93 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"testing"
+)
+
+func TestValidationErrorFields(t *testing.T) {
+	tests := map[string]struct {
+		details string
+		want    []FieldError
+	}{
+		"map of reasons": {
+			details: `{"from": ["Domain yourdomain.com is not verified"], "html": "must be at most 5MB"}`,
+			want: []FieldError{
+				{Path: "from", Reason: "Domain yourdomain.com is not verified"},
+				{Path: "html", Reason: "must be at most 5MB"},
+			},
+		},
+		"nested": {
+			details: `{"metadata": {"order-id": ["key must match ^[a-zA-Z][a-zA-Z0-9_]*$"]}, "to": {"3": "is not a valid email address"}}`,
+			want: []FieldError{
+				{Path: "metadata.order-id", Reason: "key must match ^[a-zA-Z][a-zA-Z0-9_]*$"},
+				{Path: "to[3]", Reason: "is not a valid email address"},
+			},
+		},
+		"list of objects": {
+			details: `[{"field": "to[3]", "message": "is not a valid email address"}, {"path": ["metadata", "order-id"], "reason": "invalid key"}]`,
+			want: []FieldError{
+				{Path: "metadata.order-id", Reason: "invalid key"},
+				{Path: "to[3]", Reason: "is not a valid email address"},
+			},
+		},
+		"indexed list": {
+			details: `{"attachments": [{}, {"content": ["is not valid base64"]}]}`,
+			want: []FieldError{
+				{Path: "attachments[1].content", Reason: "is not valid base64"},
+			},
+		},
+		"no details": {
+			details: `null`,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			aerr := &Error{StatusCode: http.StatusUnprocessableEntity}
+			body := `{"error":{"code":"validation_error","message":"Invalid request","details":` + test.details + `}}`
+			if err := aerr.UnmarshalJSON([]byte(body)); err != nil {
+				t.Fatalf("err should be nil: %s", err)
+			}
+			verr := aerr.ValidationError()
+			if verr == nil {
+				t.Fatal("expected a ValidationError")
+			}
+			if !reflect.DeepEqual(verr.Fields, test.want) {
+				t.Fatalf("expected %+v, got %+v", test.want, verr.Fields)
+			}
+		})
+	}
+}
+
+func TestValidationErrorAs(t *testing.T) {
+	aerr := &Error{StatusCode: http.StatusBadRequest, Details: map[string]any{"to": "is required"}}
+	err := fmt.Errorf("wrapped: %w", aerr)
+
+	var verr *ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatal("expected errors.As to find a ValidationError")
+	}
+	if verr.Err != aerr || len(verr.Fields) != 1 || verr.Fields[0].Path != "to" {
+		t.Fatalf("unexpected ValidationError %+v", verr)
+	}
+	if !errors.Is(verr, ErrValidation) {
+		t.Fatal("expected the ValidationError to match ErrValidation")
+	}
+	if got := verr.Error(); got != "validation failed: to: is required" {
+		t.Fatalf("unexpected message %q", got)
+	}
+
+	var notFound *ValidationError
+	if errors.As(&Error{StatusCode: http.StatusNotFound}, &notFound) {
+		t.Fatal("did not expect a ValidationError for a 404")
+	}
+
+	local := &ValidationError{Fields: []FieldError{{Path: "to", Reason: "is required"}}}
+	if !errors.Is(local, ErrValidation) {
+		t.Fatal("expected a client-side ValidationError to match ErrValidation")
+	}
+}
@@ -0,0 +1,52 @@
+package requestconfig
+
+import (
+	"reflect"
+	"strings"
+	"sync"
+)
+
+// idempotencyHeader is the header of the idempotency key of a request.
+const idempotencyHeader = "Idempotency-Key"
+
+// idempotentTypes caches whether params types declare an idempotency key.
+var idempotentTypes sync.Map // map[reflect.Type]bool
+
+// setIdempotencyKey sets a generated idempotency key on POST requests whose
+// params declare the Idempotency-Key header, unless a key was already set.
+func (cfg *RequestConfig) setIdempotencyKey(body any) {
+	if cfg.IdempotencyKey == nil || cfg.Request.Method != "POST" || cfg.Request.Header.Get(idempotencyHeader) != "" {
+		return
+	}
+	if !supportsIdempotencyKey(body) {
+		return
+	}
+	if key := cfg.IdempotencyKey(cfg.Context); key != "" {
+		cfg.Request.Header.Set(idempotencyHeader, key)
+	}
+}
+
+// supportsIdempotencyKey reports whether body is a params struct with a field
+// for the Idempotency-Key header.
+func supportsIdempotencyKey(body any) bool {
+	t := reflect.TypeOf(body)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return false
+	}
+	if ok, found := idempotentTypes.Load(t); found {
+		return ok.(bool)
+	}
+	ok := false
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("header"), ",")
+		if strings.EqualFold(name, idempotencyHeader) {
+			ok = true
+			break
+		}
+	}
+	idempotentTypes.Store(t, ok)
+	return ok
+}
@@ -8,8 +8,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
-	"math/rand"
 	"mime"
 	"net/http"
 	"net/url"
@@ -176,6 +174,15 @@ func NewRequestConfig(ctx context.Context, method string, u string, body any, ds
 		return nil, err
 	}
 
+	if v, ok := body.(interface{ Validate() error }); ok && cfg.ValidateParams {
+		if err := v.Validate(); err != nil {
+			return nil, err
+		}
+	}
+
+	// The key is set once, so that every retry of the request reuses it.
+	cfg.setIdempotencyKey(body)
+
 	// This must run after `cfg.Apply(...)` above in case the request timeout gets modified. We also only
 	// apply our own logic for it if it's still "0" from above. If it's not, then it was deleted or modified
 	// by the user and we should respect that.
@@ -213,6 +220,24 @@ type RequestConfig struct {
 	HTTPClient     *http.Client
 	Middlewares    []middleware
 	APIKey         string
+	// PagePrefetch is the number of pages auto-pagers may fetch concurrently. It
+	// has no effect on other requests.
+	PagePrefetch int
+	// ValidateParams makes requests call the Validate method of their params, if
+	// they have one, before being sent.
+	ValidateParams bool
+	// IdempotencyKey generates the idempotency key of POST requests which support
+	// one but were not given a key.
+	IdempotencyKey func(ctx context.Context) string
+	// RetryPolicy decides whether and when failed requests are retried, up to
+	// MaxRetries times. If nil, DefaultRetryPolicy is used.
+	RetryPolicy RetryPolicy
+	// Clock is used to wait between retries. If nil, the system clock is used.
+	Clock Clock
+	// CallHooks are called before the first attempt of a request, and the
+	// functions they return once the last attempt is done. Unlike middlewares,
+	// which are called for every attempt, they observe the request as a whole.
+	CallHooks []CallHook
 	// If ResponseBodyInto not nil, then we will attempt to deserialize into
 	// ResponseBodyInto. If Destination is a []byte, then it will return the body as
 	// is.
@@ -227,6 +252,11 @@ type RequestConfig struct {
 // but it is redeclared here for circular dependency issues.
 type middleware = func(*http.Request, middlewareNext) (*http.Response, error)
 
+// CallHook is called before the first attempt of a request with the request, and
+// returns the context of its attempts and a function called with the final
+// response and error.
+type CallHook = func(req *http.Request) (context.Context, func(res *http.Response, err error))
+
 // middlewareNext is exactly the same type as the MiddlewareNext type found in the [option] package,
 // but it is redeclared here for circular dependency issues.
 type middlewareNext = func(*http.Request) (*http.Response, error)
@@ -237,34 +267,7 @@ func applyMiddleware(middleware middleware, next middlewareNext) middlewareNext
 	}
 }
 
-func shouldRetry(req *http.Request, res *http.Response) bool {
-	// If there is no way to recover the Body, then we shouldn't retry.
-	if req.Body != nil && req.GetBody == nil {
-		return false
-	}
-
-	// If there is no response, that indicates that there is a connection error
-	// so we retry the request.
-	if res == nil {
-		return true
-	}
-
-	// If the header explicitly wants a retry behavior, respect that over the
-	// http status code.
-	if res.Header.Get("x-should-retry") == "true" {
-		return true
-	}
-	if res.Header.Get("x-should-retry") == "false" {
-		return false
-	}
-
-	return res.StatusCode == http.StatusRequestTimeout ||
-		res.StatusCode == http.StatusConflict ||
-		res.StatusCode == http.StatusTooManyRequests ||
-		res.StatusCode >= http.StatusInternalServerError
-}
-
-func parseRetryAfterHeader(resp *http.Response) (time.Duration, bool) {
+func parseRetryAfterHeader(resp *http.Response, now time.Time) (time.Duration, bool) {
 	if resp == nil {
 		return 0, false
 	}
@@ -298,7 +301,7 @@ func parseRetryAfterHeader(resp *http.Response) (time.Duration, bool) {
 				if err != nil {
 					return 0, false
 				}
-				return time.Until(t), true
+				return t.Sub(now), true
 			},
 		},
 	}
@@ -354,25 +357,6 @@ func (b *bodyWithTimeout) Close() error {
 	return err
 }
 
-func retryDelay(res *http.Response, retryCount int) time.Duration {
-	// If the API asks us to wait a certain amount of time (and it's a reasonable amount),
-	// just do what it says.
-
-	if retryAfterDelay, ok := parseRetryAfterHeader(res); ok && 0 <= retryAfterDelay && retryAfterDelay < time.Minute {
-		return retryAfterDelay
-	}
-
-	maxDelay := 8 * time.Second
-	delay := time.Duration(0.5 * float64(time.Second) * math.Pow(2, float64(retryCount)))
-	if delay > maxDelay {
-		delay = maxDelay
-	}
-
-	jitter := rand.Int63n(int64(delay / 4))
-	delay -= time.Duration(jitter)
-	return delay
-}
-
 func (cfg *RequestConfig) Execute() (err error) {
 	if cfg.BaseURL == nil {
 		if cfg.DefaultBaseURL != nil {
@@ -387,6 +371,13 @@ func (cfg *RequestConfig) Execute() (err error) {
 		return err
 	}
 
+	var res *http.Response
+	for _, hook := range cfg.CallHooks {
+		ctx, end := hook(cfg.Request)
+		cfg.Request = cfg.Request.WithContext(ctx)
+		defer func() { end(res, err) }()
+	}
+
 	if cfg.Body != nil && cfg.Request.Body == nil {
 		switch body := cfg.Body.(type) {
 		case *bytes.Buffer:
@@ -421,7 +412,6 @@ func (cfg *RequestConfig) Execute() (err error) {
 	// Don't send the current retry count in the headers if the caller modified the header defaults.
 	shouldSendRetryCount := cfg.Request.Header.Get("X-Stainless-Retry-Count") == "0"
 
-	var res *http.Response
 	var cancel context.CancelFunc
 	for retryCount := 0; retryCount <= cfg.MaxRetries; retryCount += 1 {
 		ctx := cfg.Request.Context()
@@ -444,7 +434,23 @@ func (cfg *RequestConfig) Execute() (err error) {
 		if ctx != nil && ctx.Err() != nil {
 			return ctx.Err()
 		}
-		if !shouldRetry(cfg.Request, res) || retryCount >= cfg.MaxRetries {
+		// If there is no way to recover the Body, then we shouldn't retry.
+		if retryCount >= cfg.MaxRetries || cfg.Request.Body != nil && cfg.Request.GetBody == nil {
+			break
+		}
+		delay, retry := cfg.retryPolicy().RetryDelay(RetryAttempt{
+			Request:  req,
+			Response: res,
+			Err:      err,
+			Attempt:  retryCount + 1,
+			Now:      cfg.clock().Now(),
+		})
+		if !retry {
+			break
+		}
+		// Give up on retrying if the request would time out while waiting, and
+		// return the error of the last attempt instead.
+		if !isBeforeContextDeadline(cfg.clock().Now().Add(delay), cfg.Request.Context()) {
 			break
 		}
 
@@ -466,7 +472,11 @@ func (cfg *RequestConfig) Execute() (err error) {
 			res.Body.Close()
 		}
 
-		time.Sleep(retryDelay(res, retryCount))
+		select {
+		case <-cfg.clock().After(delay):
+		case <-cfg.Request.Context().Done():
+			return cfg.Request.Context().Err()
+		}
 	}
 
 	// Save *http.Response if it is requested to, even if there was an error making the request. This is
@@ -585,6 +595,12 @@ func (cfg *RequestConfig) Clone(ctx context.Context) *RequestConfig {
 		HTTPClient:     cfg.HTTPClient,
 		Middlewares:    cfg.Middlewares,
 		APIKey:         cfg.APIKey,
+		PagePrefetch:   cfg.PagePrefetch,
+		ValidateParams: cfg.ValidateParams,
+		IdempotencyKey: cfg.IdempotencyKey,
+		RetryPolicy:    cfg.RetryPolicy,
+		Clock:          cfg.Clock,
+		CallHooks:      cfg.CallHooks,
 	}
 
 	return new
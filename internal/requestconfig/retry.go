@@ -0,0 +1,130 @@
+package requestconfig
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+// RetryAttempt is a failed attempt to send a request, which a [RetryPolicy]
+// may decide to retry.
+type RetryAttempt struct {
+	// Request is the request which was sent. Its body has been read.
+	Request *http.Request
+	// Response is the response to the request, or nil if it failed with Err.
+	Response *http.Response
+	Err      error
+	// Attempt is the number of attempts made so far, starting at 1.
+	Attempt int
+	// Now is the time at which the attempt failed, according to the [Clock] of
+	// the request.
+	Now time.Time
+}
+
+// Retryable reports whether the attempt failed in a way which may not happen
+// again: a connection error, or a 408, 409, 429 or 5xx response. The API can
+// override this with the x-should-retry response header, and errors returned
+// by middlewares with a Retryable() bool method.
+func (a RetryAttempt) Retryable() bool {
+	var retryable interface{ Retryable() bool }
+	if errors.As(a.Err, &retryable) {
+		return retryable.Retryable()
+	}
+
+	// If there is no response, that indicates that there is a connection error
+	// so we retry the request.
+	if a.Response == nil {
+		return true
+	}
+
+	// If the header explicitly wants a retry behavior, respect that over the
+	// http status code.
+	if a.Response.Header.Get("x-should-retry") == "true" {
+		return true
+	}
+	if a.Response.Header.Get("x-should-retry") == "false" {
+		return false
+	}
+
+	return a.Response.StatusCode == http.StatusRequestTimeout ||
+		a.Response.StatusCode == http.StatusConflict ||
+		a.Response.StatusCode == http.StatusTooManyRequests ||
+		a.Response.StatusCode >= http.StatusInternalServerError
+}
+
+// RetryAfter returns the delay requested by the Retry-After-Ms or Retry-After
+// header of the response, if any.
+func (a RetryAttempt) RetryAfter() (time.Duration, bool) {
+	d, ok := parseRetryAfterHeader(a.Response, a.Now)
+	if !ok || d < 0 {
+		return 0, false
+	}
+	return d, true
+}
+
+// RetryPolicy decides whether a failed attempt to send a request is retried,
+// and how long to wait before retrying it.
+type RetryPolicy interface {
+	RetryDelay(attempt RetryAttempt) (delay time.Duration, retry bool)
+}
+
+// DefaultRetryPolicy retries retryable attempts after an exponential backoff
+// starting at 0.5s and capped at 8s, reduced by up to 25% of jitter. A
+// Retry-After of less than a minute is used instead, when the response has one.
+var DefaultRetryPolicy RetryPolicy = defaultRetryPolicy{}
+
+type defaultRetryPolicy struct{}
+
+func (defaultRetryPolicy) RetryDelay(a RetryAttempt) (time.Duration, bool) {
+	if !a.Retryable() {
+		return 0, false
+	}
+
+	// If the API asks us to wait a certain amount of time (and it's a reasonable amount),
+	// just do what it says.
+	if retryAfterDelay, ok := a.RetryAfter(); ok && retryAfterDelay < time.Minute {
+		return retryAfterDelay, true
+	}
+
+	maxDelay := 8 * time.Second
+	delay := time.Duration(0.5 * float64(time.Second) * math.Pow(2, float64(a.Attempt-1)))
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+
+	jitter := rand.Int63n(int64(delay / 4))
+	delay -= time.Duration(jitter)
+	return delay, true
+}
+
+// Clock tells the time and waits between retries. It can be replaced to test
+// retries without waiting.
+type Clock interface {
+	Now() time.Time
+	// After returns a channel on which the time is sent after d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the [Clock] used by default.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (cfg *RequestConfig) retryPolicy() RetryPolicy {
+	if cfg.RetryPolicy != nil {
+		return cfg.RetryPolicy
+	}
+	return DefaultRetryPolicy
+}
+
+func (cfg *RequestConfig) clock() Clock {
+	if cfg.Clock != nil {
+		return cfg.Clock
+	}
+	return SystemClock
+}
@@ -0,0 +1,67 @@
+//go:build go1.23
+
+package ark
+
+import (
+	"context"
+	"iter"
+
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// All returns an iterator over every email matching query, see
+// [EmailService.ListAutoPaging]. No request is made until the iterator is ranged
+// over, and breaking out of the loop stops any further requests.
+func (r *EmailService) All(ctx context.Context, query EmailListParams, opts ...option.RequestOption) iter.Seq2[EmailListResponse, error] {
+	return func(yield func(EmailListResponse, error) bool) {
+		r.ListAutoPaging(ctx, query, opts...).All()(yield)
+	}
+}
+
+// All returns an iterator over every log entry matching query, see
+// [LogService.ListAutoPaging].
+func (r *LogService) All(ctx context.Context, query LogListParams, opts ...option.RequestOption) iter.Seq2[LogEntry, error] {
+	return func(yield func(LogEntry, error) bool) {
+		r.ListAutoPaging(ctx, query, opts...).All()(yield)
+	}
+}
+
+// All returns an iterator over every tenant matching query, see
+// [TenantService.ListAutoPaging].
+func (r *TenantService) All(ctx context.Context, query TenantListParams, opts ...option.RequestOption) iter.Seq2[Tenant, error] {
+	return func(yield func(Tenant, error) bool) {
+		r.ListAutoPaging(ctx, query, opts...).All()(yield)
+	}
+}
+
+// All returns an iterator over every credential of a tenant, see
+// [TenantCredentialService.ListAutoPaging].
+func (r *TenantCredentialService) All(ctx context.Context, tenantID string, query TenantCredentialListParams, opts ...option.RequestOption) iter.Seq2[TenantCredentialListResponse, error] {
+	return func(yield func(TenantCredentialListResponse, error) bool) {
+		r.ListAutoPaging(ctx, tenantID, query, opts...).All()(yield)
+	}
+}
+
+// All returns an iterator over every suppressed address of a tenant, see
+// [TenantSuppressionService.ListAutoPaging].
+func (r *TenantSuppressionService) All(ctx context.Context, tenantID string, query TenantSuppressionListParams, opts ...option.RequestOption) iter.Seq2[TenantSuppressionListResponse, error] {
+	return func(yield func(TenantSuppressionListResponse, error) bool) {
+		r.ListAutoPaging(ctx, tenantID, query, opts...).All()(yield)
+	}
+}
+
+// TenantsAll returns an iterator over the usage of every tenant matching query,
+// see [UsageService.ListTenantsAutoPaging].
+func (r *UsageService) TenantsAll(ctx context.Context, query UsageListTenantsParams, opts ...option.RequestOption) iter.Seq2[TenantUsageItem, error] {
+	return func(yield func(TenantUsageItem, error) bool) {
+		r.ListTenantsAutoPaging(ctx, query, opts...).All()(yield)
+	}
+}
+
+// DeliveriesAll returns an iterator over every platform webhook delivery
+// matching query, see [PlatformWebhookService.ListDeliveriesAutoPaging].
+func (r *PlatformWebhookService) DeliveriesAll(ctx context.Context, query PlatformWebhookListDeliveriesParams, opts ...option.RequestOption) iter.Seq2[PlatformWebhookListDeliveriesResponse, error] {
+	return func(yield func(PlatformWebhookListDeliveriesResponse, error) bool) {
+		r.ListDeliveriesAutoPaging(ctx, query, opts...).All()(yield)
+	}
+}
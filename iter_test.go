@@ -0,0 +1,80 @@
+//go:build go1.23
+
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+func TestAllStopsOnBreak(t *testing.T) {
+	api := &pagedAPI{totalPages: 5, perPage: 2}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	seq := client.Emails.All(context.Background(), ark.EmailListParams{})
+	if len(api.Requested()) != 0 {
+		t.Fatalf("expected no requests before ranging, got %v", api.Requested())
+	}
+
+	var ids []string
+	for email, err := range seq {
+		if err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+		ids = append(ids, email.ID)
+		if len(ids) == 3 {
+			break
+		}
+	}
+	if want := []string{"msg_1_0", "msg_1_1", "msg_2_0"}; !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	if want := []int{1, 2}; !reflect.DeepEqual(api.Requested(), want) {
+		t.Fatalf("expected pages %v to be requested, got %v", want, api.Requested())
+	}
+}
+
+func TestAllYieldsError(t *testing.T) {
+	api := &pagedAPI{totalPages: 3, perPage: 1, failPage: 2}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	count := 0
+	var apierr *ark.Error
+	for _, err := range client.Emails.All(context.Background(), ark.EmailListParams{}) {
+		if err != nil {
+			if !errors.As(err, &apierr) {
+				t.Fatalf("expected an *ark.Error, got %v", err)
+			}
+			continue
+		}
+		count++
+	}
+	if count != 1 || apierr == nil || apierr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected one email followed by a 500 error, got %d emails and %v", count, apierr)
+	}
+}
+
+func TestPages(t *testing.T) {
+	api := &pagedAPI{totalPages: 3, perPage: 2}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	var pages []int64
+	for page, err := range client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}).Pages() {
+		if err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+		if len(page.Data) != 2 {
+			t.Fatalf("expected whole pages, got %d emails", len(page.Data))
+		}
+		pages = append(pages, page.Page)
+	}
+	if want := []int64{1, 2, 3}; !reflect.DeepEqual(pages, want) {
+		t.Fatalf("expected pages %v, got %v", want, pages)
+	}
+}
@@ -0,0 +1,201 @@
+package ark_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// jsonLogger returns a logger writing the records of level and above to buf as
+// JSON.
+func jsonLogger(buf *bytes.Buffer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(buf, &slog.HandlerOptions{Level: level}))
+}
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("invalid log line %q: %s", line, err)
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	attempts := 0
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			attempts++
+			if attempts == 1 {
+				return jsonResponse(http.StatusServiceUnavailable, `{"error":{"code":"unavailable"},"meta":{"requestId":"req_1"}}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{"success":true,"meta":{"requestId":"req_2"}}`), nil
+		},
+		option.WithLogger(jsonLogger(&buf, slog.LevelInfo)),
+		option.WithClock(newFakeClock()),
+	)
+
+	if _, err := client.Logs.Get(context.Background(), "req_123"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	records := logRecords(t, &buf)
+	if len(records) != 2 {
+		t.Fatalf("expected a record per attempt, got %v", records)
+	}
+	for i, want := range []map[string]any{
+		{"level": "WARN", "msg": "ark request", "method": "GET", "path": "/v1/logs/req_123", "retry": 0.0, "status": 503.0, "request_id": "req_1"},
+		{"level": "INFO", "msg": "ark request", "method": "GET", "path": "/v1/logs/req_123", "retry": 1.0, "status": 200.0, "request_id": "req_2"},
+	} {
+		for key, value := range want {
+			if records[i][key] != value {
+				t.Fatalf("expected %s to be %v in record %d, got %v", key, value, i, records[i])
+			}
+		}
+		if _, ok := records[i]["duration"]; !ok {
+			t.Fatalf("expected the duration in record %d, got %v", i, records[i])
+		}
+		if _, ok := records[i]["request"]; ok {
+			t.Fatalf("expected no bodies at the Info level, got %v", records[i])
+		}
+	}
+}
+
+func TestLoggerRedaction(t *testing.T) {
+	var buf bytes.Buffer
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			if strings.Contains(req.URL.Path, "/credentials/") {
+				return jsonResponse(http.StatusOK, `{"success":true,"data":{"id":1,"name":"prod","key":"secret-credential-key"}}`), nil
+			}
+			return jsonResponse(http.StatusOK, `{"success":true,"data":{"id":"msg_123"}}`), nil
+		},
+		option.WithAPIKey("secret-api-key"),
+		option.WithLogger(jsonLogger(&buf, slog.LevelDebug)),
+	)
+
+	attachment, err := ark.AttachmentFromBytes("invoice.pdf", []byte("secret-attachment"))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Emails.Send(context.Background(), ark.EmailSendParams{
+		From:        "hello@yourdomain.com",
+		Subject:     "Your invoice",
+		To:          []string{"user@example.com"},
+		Text:        ark.String("See attached"),
+		Attachments: []ark.EmailSendParamsAttachment{attachment},
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Emails.SendRaw(context.Background(), ark.EmailSendRawParams{
+		From:       "hello@yourdomain.com",
+		To:         []string{"user@example.com"},
+		RawMessage: "c2VjcmV0LXJhdy1tZXNzYWdl",
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	_, err = client.Tenants.Credentials.Get(context.Background(), 1, ark.TenantCredentialGetParams{
+		TenantID: "tn_123",
+		Reveal:   ark.Bool(true),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	logs := buf.String()
+	for _, secret := range []string{"secret-api-key", "c2VjcmV0LWF0dGFjaG1lbnQ=", "c2VjcmV0LXJhdy1tZXNzYWdl", "secret-credential-key"} {
+		if strings.Contains(logs, secret) {
+			t.Fatalf("expected %q to be redacted from the logs:\n%s", secret, logs)
+		}
+	}
+
+	records := logRecords(t, &buf)
+	if len(records) != 3 {
+		t.Fatalf("expected 3 records, got %d", len(records))
+	}
+	request := records[0]["request"].(map[string]any)
+	if request["headers"].(map[string]any)["Authorization"] != "[REDACTED]" {
+		t.Fatalf("expected the Authorization header to be redacted, got %v", request["headers"])
+	}
+	for _, want := range []string{`"subject":"Your invoice"`, `"content":"[REDACTED]"`, `"filename":"invoice.pdf"`} {
+		if !strings.Contains(request["body"].(string), want) {
+			t.Fatalf("expected the request body to contain %s, got %s", want, request["body"])
+		}
+	}
+	if body := records[0]["response"].(map[string]any)["body"].(string); !strings.Contains(body, `"msg_123"`) {
+		t.Fatalf("expected the response body, got %s", body)
+	}
+	if body := records[2]["response"].(map[string]any)["body"].(string); !strings.Contains(body, `"key":"[REDACTED]"`) || !strings.Contains(body, `"name":"prod"`) {
+		t.Fatalf("expected the credential key to be redacted, got %s", body)
+	}
+}
+
+func TestLoggerLargeBody(t *testing.T) {
+	var buf bytes.Buffer
+	var opened int
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			return jsonResponse(http.StatusOK, `{"success":true,"data":{"id":"msg_123"}}`), nil
+		},
+		option.WithMiddleware(func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+			if getBody := req.GetBody; getBody != nil {
+				req.GetBody = func() (io.ReadCloser, error) {
+					opened++
+					return getBody()
+				}
+			}
+			return next(req)
+		}),
+		option.WithLogger(jsonLogger(&buf, slog.LevelDebug)),
+	)
+
+	_, err := client.Emails.SendRaw(context.Background(), ark.EmailSendRawParams{
+		From:       "hello@yourdomain.com",
+		To:         []string{"user@example.com"},
+		RawMessage: strings.Repeat("A", 20<<10),
+	})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if opened != 0 {
+		t.Fatalf("expected the request body not to be read for the logs, got %d reads", opened)
+	}
+	records := logRecords(t, &buf)
+	if body := records[0]["request"].(map[string]any)["body"]; body != "[truncated JSON body]" {
+		t.Fatalf("expected the request body to be truncated, got %v", body)
+	}
+}
+
+func TestLoggerLargeResponseBody(t *testing.T) {
+	var buf bytes.Buffer
+	from := strings.Repeat("a", 20<<10) + "@yourdomain.com"
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return jsonResponse(http.StatusOK, `{"success":true,"data":{"id":"msg_123","from":"`+from+`"}}`), nil
+	}, option.WithLogger(jsonLogger(&buf, slog.LevelDebug)))
+
+	res, err := client.Emails.Get(context.Background(), "msg_123", ark.EmailGetParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if res.Data.From != from {
+		t.Fatalf("expected the caller to read the full response body, got %d bytes", len(res.Data.From))
+	}
+	records := logRecords(t, &buf)
+	if body := records[0]["response"].(map[string]any)["body"]; body != "[truncated JSON body]" {
+		t.Fatalf("expected the response body to be truncated, got %v", body)
+	}
+}
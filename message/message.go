@@ -0,0 +1,473 @@
+// Package message composes RFC 5322 email messages for
+// [ark.EmailService.SendRaw].
+//
+//	msg := &message.Message{
+//		From:    "Acme <hello@yourdomain.com>",
+//		To:      []string{"user@example.com"},
+//		Subject: "Welcome",
+//		Text:    "Welcome to Acme!",
+//		HTML:    "<h1>Welcome to Acme!</h1>",
+//	}
+//	params, err := msg.Params()
+//	if err != nil {
+//		return err
+//	}
+//	res, err := client.Emails.SendRaw(ctx, params)
+//
+// [ark.EmailService.SendRaw]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.SendRaw
+package message
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+)
+
+// maxSize is the largest message accepted by the API.
+const maxSize = 14 << 20
+
+// maxLineLen is the length at which header lines are folded, see RFC 5322
+// section 2.1.1.
+const maxLineLen = 78
+
+// reservedHeaders are the headers written from the fields of a [Message].
+var reservedHeaders = map[string]bool{
+	"From":                      true,
+	"To":                        true,
+	"Cc":                        true,
+	"Bcc":                       true,
+	"Reply-To":                  true,
+	"Subject":                   true,
+	"Date":                      true,
+	"Message-Id":                true,
+	"Mime-Version":              true,
+	"Content-Type":              true,
+	"Content-Transfer-Encoding": true,
+}
+
+// Message is an email message. Addresses can either be plain, like
+// "user@example.com", or include a display name, like
+// "Jane Doe <user@example.com>".
+type Message struct {
+	From string
+	To   []string
+	Cc   []string
+	// Bcc recipients receive the message, but are not listed in its headers.
+	Bcc     []string
+	ReplyTo string
+	Subject string
+	// Text and HTML are the bodies of the message. If both are set, the message
+	// is sent as multipart/alternative so that clients can pick either.
+	Text string
+	HTML string
+	// Attachments, including inline attachments which are referenced from HTML.
+	Attachments []Attachment
+	// Headers are additional headers, e.g. "List-Unsubscribe". They must not
+	// contain any of the headers set from the other fields.
+	Headers map[string]string
+	// Date of the message, the current time if zero.
+	Date time.Time
+	// MessageID is the Message-ID header without angle brackets. A random ID at
+	// the domain of From is used if empty.
+	MessageID string
+}
+
+// Attachment is a file attached to a [Message].
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Content     []byte
+	// ContentID makes the attachment inline, so that it can be referenced from
+	// the HTML body, e.g. as `<img src="cid:logo">` for the content ID "logo".
+	ContentID string
+}
+
+// Inline returns an inline attachment with the content of a, as returned by
+// [ark.AttachmentFromFile] and the other attachment constructors, which the HTML
+// body references as `<img src="cid:contentID">`. Inline attachments can only be
+// sent in raw messages, as [ark.EmailSendParams] has no content ID property.
+//
+// [ark.AttachmentFromFile]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#AttachmentFromFile
+// [ark.EmailSendParams]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailSendParams
+func Inline(a ark.EmailSendParamsAttachment, contentID string) (Attachment, error) {
+	if contentID == "" {
+		return Attachment{}, errors.New("message: inline attachment without content ID")
+	}
+	content, err := base64.StdEncoding.DecodeString(a.Content)
+	if err != nil {
+		return Attachment{}, fmt.Errorf("message: attachment %q is not base64-encoded: %w", a.Filename, err)
+	}
+	return Attachment{
+		Filename:    a.Filename,
+		ContentType: a.ContentType,
+		Content:     content,
+		ContentID:   contentID,
+	}, nil
+}
+
+// Bytes returns the message in RFC 5322 format.
+func (m *Message) Bytes() ([]byte, error) {
+	var buf bytes.Buffer
+	if _, err := m.WriteTo(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// Params returns params for [ark.EmailService.SendRaw] which send the message to
+// all of its To, Cc and Bcc recipients. It returns [ark.ErrMessageTooLarge] if
+// the message exceeds the 14MB limit of the API.
+//
+// [ark.EmailService.SendRaw]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.SendRaw
+func (m *Message) Params() (ark.EmailSendRawParams, error) {
+	raw, err := m.Bytes()
+	if err != nil {
+		return ark.EmailSendRawParams{}, err
+	}
+	if len(raw) > maxSize {
+		return ark.EmailSendRawParams{}, fmt.Errorf("%w: the message is %d bytes", ark.ErrMessageTooLarge, len(raw))
+	}
+	to, err := m.Recipients()
+	if err != nil {
+		return ark.EmailSendRawParams{}, err
+	}
+	return ark.EmailSendRawParams{
+		From:       m.From,
+		To:         to,
+		RawMessage: base64.StdEncoding.EncodeToString(raw),
+	}, nil
+}
+
+// Recipients returns the addresses of all To, Cc and Bcc recipients, without
+// display names.
+func (m *Message) Recipients() ([]string, error) {
+	var out []string
+	for _, list := range [][]string{m.To, m.Cc, m.Bcc} {
+		addrs, err := parseAddresses(list)
+		if err != nil {
+			return nil, err
+		}
+		for _, addr := range addrs {
+			out = append(out, addr.Address)
+		}
+	}
+	return out, nil
+}
+
+// WriteTo writes the message in RFC 5322 format to w.
+func (m *Message) WriteTo(w io.Writer) (int64, error) {
+	from, err := mail.ParseAddress(m.From)
+	if err != nil {
+		return 0, fmt.Errorf("message: invalid From address %q: %w", m.From, err)
+	}
+	if len(m.To)+len(m.Cc)+len(m.Bcc) == 0 {
+		return 0, errors.New("message: no recipients")
+	}
+
+	cw := &countingWriter{w: w}
+	h := &headerWriter{w: cw}
+	h.addresses("From", []*mail.Address{from})
+	for _, field := range []struct {
+		name string
+		list []string
+	}{{"To", m.To}, {"Cc", m.Cc}} {
+		addrs, err := parseAddresses(field.list)
+		if err != nil {
+			return cw.n, err
+		}
+		h.addresses(field.name, addrs)
+	}
+	if m.ReplyTo != "" {
+		replyTo, err := mail.ParseAddress(m.ReplyTo)
+		if err != nil {
+			return cw.n, fmt.Errorf("message: invalid Reply-To address %q: %w", m.ReplyTo, err)
+		}
+		h.addresses("Reply-To", []*mail.Address{replyTo})
+	}
+	h.text("Subject", mime.QEncoding.Encode("utf-8", m.Subject))
+	date := m.Date
+	if date.IsZero() {
+		date = time.Now()
+	}
+	h.text("Date", date.Format(time.RFC1123Z))
+	id := m.MessageID
+	if id == "" {
+		id = newMessageID(from.Address)
+	}
+	h.text("Message-ID", "<"+id+">")
+	h.text("MIME-Version", "1.0")
+
+	keys := make([]string, 0, len(m.Headers))
+	for k := range m.Headers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		name := textproto.CanonicalMIMEHeaderKey(k)
+		if reservedHeaders[name] {
+			return cw.n, fmt.Errorf("message: header %q must be set with the fields of Message", name)
+		}
+		h.text(name, mime.QEncoding.Encode("utf-8", m.Headers[k]))
+	}
+	if h.err != nil {
+		return cw.n, h.err
+	}
+
+	err = m.writeBody(cw, h)
+	return cw.n, err
+}
+
+// writeBody writes the Content-Type header of the message and its body. The
+// structure of the body depends on which parts are present:
+//
+//	multipart/mixed
+//	├── multipart/alternative
+//	│   ├── text/plain
+//	│   └── multipart/related
+//	│       ├── text/html
+//	│       └── inline attachments
+//	└── attachments
+//
+// where each multipart with a single part is replaced by that part.
+func (m *Message) writeBody(w io.Writer, h *headerWriter) error {
+	var inline, attached []Attachment
+	for _, a := range m.Attachments {
+		if a.ContentID != "" && m.HTML != "" {
+			inline = append(inline, a)
+		} else {
+			attached = append(attached, a)
+		}
+	}
+
+	html := func(pw partWriter) error {
+		return writeMultipart(pw, "related", inline, func(pw partWriter) error {
+			return writeText(pw, "text/html", m.HTML)
+		})
+	}
+	var bodies []func(partWriter) error
+	if m.Text != "" || m.HTML == "" {
+		bodies = append(bodies, func(pw partWriter) error { return writeText(pw, "text/plain", m.Text) })
+	}
+	if m.HTML != "" {
+		bodies = append(bodies, html)
+	}
+	body := func(pw partWriter) error {
+		if len(bodies) == 1 {
+			return bodies[0](pw)
+		}
+		return writeAlternative(pw, bodies)
+	}
+
+	return writeMultipart(func(header textproto.MIMEHeader) (io.Writer, error) {
+		keys := make([]string, 0, len(header))
+		for k := range header {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			h.text(k, header.Get(k))
+		}
+		if h.err != nil {
+			return nil, h.err
+		}
+		_, err := io.WriteString(w, "\r\n")
+		return w, err
+	}, "mixed", attached, body)
+}
+
+// partWriter starts a new part with the given header and returns a writer for
+// its body.
+type partWriter func(header textproto.MIMEHeader) (io.Writer, error)
+
+// writeMultipart writes a multipart of the given subtype with the part written by
+// first, followed by the attachments. If there are no attachments, only the
+// first part is written.
+func writeMultipart(pw partWriter, subtype string, attachments []Attachment, first func(partWriter) error) error {
+	if len(attachments) == 0 {
+		return first(pw)
+	}
+	return writeParts(pw, subtype, func(mw *multipart.Writer) error {
+		if err := first(mw.CreatePart); err != nil {
+			return err
+		}
+		for _, a := range attachments {
+			if err := writeAttachment(mw.CreatePart, a, subtype == "related"); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func writeAlternative(pw partWriter, bodies []func(partWriter) error) error {
+	return writeParts(pw, "alternative", func(mw *multipart.Writer) error {
+		for _, body := range bodies {
+			if err := body(mw.CreatePart); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func writeParts(pw partWriter, subtype string, parts func(*multipart.Writer) error) error {
+	var boundary [16]byte
+	rand.Read(boundary[:])
+	b := hex.EncodeToString(boundary[:])
+	w, err := pw(textproto.MIMEHeader{
+		"Content-Type": {mime.FormatMediaType("multipart/"+subtype, map[string]string{"boundary": b})},
+	})
+	if err != nil {
+		return err
+	}
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(b); err != nil {
+		return err
+	}
+	if err := parts(mw); err != nil {
+		return err
+	}
+	return mw.Close()
+}
+
+func writeText(pw partWriter, contentType string, text string) error {
+	w, err := pw(textproto.MIMEHeader{
+		"Content-Type":              {mime.FormatMediaType(contentType, map[string]string{"charset": "utf-8"})},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+	qw := quotedprintable.NewWriter(w)
+	if _, err := io.WriteString(qw, text); err != nil {
+		return err
+	}
+	return qw.Close()
+}
+
+func writeAttachment(pw partWriter, a Attachment, inline bool) error {
+	contentType := a.ContentType
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+	disposition := "attachment"
+	if inline {
+		disposition = "inline"
+	}
+	header := textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"base64"},
+	}
+	if a.Filename != "" {
+		header.Set("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": a.Filename}))
+	} else {
+		header.Set("Content-Disposition", disposition)
+	}
+	if inline {
+		header.Set("Content-ID", "<"+a.ContentID+">")
+	}
+	w, err := pw(header)
+	if err != nil {
+		return err
+	}
+	// Base64 encoded lines must not be longer than 76 characters, see RFC 2045
+	// section 6.8.
+	line := make([]byte, 76+2)
+	for content := a.Content; len(content) > 0; {
+		n := min(len(content), 57)
+		base64.StdEncoding.Encode(line, content[:n])
+		encoded := base64.StdEncoding.EncodedLen(n)
+		copy(line[encoded:], "\r\n")
+		if _, err := w.Write(line[:encoded+2]); err != nil {
+			return err
+		}
+		content = content[n:]
+	}
+	return nil
+}
+
+func parseAddresses(list []string) ([]*mail.Address, error) {
+	addrs := make([]*mail.Address, 0, len(list))
+	for _, s := range list {
+		addr, err := mail.ParseAddress(s)
+		if err != nil {
+			return nil, fmt.Errorf("message: invalid address %q: %w", s, err)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func newMessageID(from string) string {
+	domain := "localhost"
+	if i := strings.LastIndexByte(from, '@'); i >= 0 {
+		domain = from[i+1:]
+	}
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:]) + "@" + domain
+}
+
+// headerWriter writes header fields, folding lines longer than maxLineLen at
+// whitespace. Words which are too long to fit on a line, like the encoded-words
+// of a long subject, are put on a line of their own. The first error is kept in
+// err.
+type headerWriter struct {
+	w   io.Writer
+	err error
+}
+
+func (h *headerWriter) addresses(name string, addrs []*mail.Address) {
+	if len(addrs) == 0 {
+		return
+	}
+	formatted := make([]string, len(addrs))
+	for i, addr := range addrs {
+		formatted[i] = addr.String()
+	}
+	h.text(name, strings.Join(formatted, ", "))
+}
+
+func (h *headerWriter) text(name, value string) {
+	if h.err != nil {
+		return
+	}
+	var b strings.Builder
+	line := name + ":"
+	for _, word := range strings.Split(value, " ") {
+		if len(line)+1+len(word) > maxLineLen && line != "" {
+			b.WriteString(line)
+			b.WriteString("\r\n")
+			line = ""
+		}
+		line += " " + word
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+	_, h.err = io.WriteString(h.w, b.String())
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
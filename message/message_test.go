@@ -0,0 +1,296 @@
+package message_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/message"
+)
+
+// part is a decoded MIME part, with the parts of multiparts in Parts.
+type part struct {
+	ContentType string
+	Header      map[string][]string
+	Body        string
+	Parts       []part
+}
+
+func readPart(t *testing.T, header map[string][]string, body io.Reader) part {
+	t.Helper()
+	mediaType, params, err := mime.ParseMediaType(firstValue(header, "Content-Type"))
+	if err != nil {
+		t.Fatalf("invalid Content-Type: %s", err)
+	}
+	p := part{ContentType: mediaType, Header: header}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		mr := multipart.NewReader(body, params["boundary"])
+		for {
+			next, err := mr.NextRawPart()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("err should be nil: %s", err)
+			}
+			p.Parts = append(p.Parts, readPart(t, next.Header, next))
+		}
+		return p
+	}
+	switch firstValue(header, "Content-Transfer-Encoding") {
+	case "quoted-printable":
+		body = quotedprintable.NewReader(body)
+	case "base64":
+		body = base64.NewDecoder(base64.StdEncoding, body)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	p.Body = string(data)
+	return p
+}
+
+func firstValue(header map[string][]string, key string) string {
+	if v := header[key]; len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+func parse(t *testing.T, raw []byte) (*mail.Message, part) {
+	t.Helper()
+	msg, err := mail.ReadMessage(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	return msg, readPart(t, msg.Header, msg.Body)
+}
+
+func structure(p part) string {
+	if len(p.Parts) == 0 {
+		return p.ContentType
+	}
+	children := make([]string, len(p.Parts))
+	for i, child := range p.Parts {
+		children[i] = structure(child)
+	}
+	return p.ContentType + "(" + strings.Join(children, ", ") + ")"
+}
+
+func TestMessageHeaders(t *testing.T) {
+	subject := "Grüße aus Berlin, " + strings.Repeat("und noch viel mehr ", 6)
+	msg := &message.Message{
+		From:      `"Acme Support" <support@yourdomain.com>`,
+		To:        []string{"Jörg Müller <jorg@example.com>", "user@example.com"},
+		Cc:        []string{"cc@example.com"},
+		Bcc:       []string{"bcc@example.com"},
+		ReplyTo:   "replies@yourdomain.com",
+		Subject:   subject,
+		Text:      "Hallo!",
+		Headers:   map[string]string{"list-unsubscribe": "<https://yourdomain.com/unsubscribe>"},
+		Date:      time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		MessageID: "abc@yourdomain.com",
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+
+	head, _, _ := strings.Cut(string(raw), "\r\n\r\n")
+	for _, line := range strings.Split(head, "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("expected header lines to be folded, got %q", line)
+		}
+	}
+
+	parsed, body := parse(t, raw)
+	var dec mime.WordDecoder
+	if got, _ := dec.DecodeHeader(parsed.Header.Get("Subject")); got != subject {
+		t.Errorf("expected subject %q, got %q", subject, got)
+	}
+	to, err := parsed.Header.AddressList("To")
+	if err != nil || len(to) != 2 || to[0].Name != "Jörg Müller" || to[1].Address != "user@example.com" {
+		t.Errorf("unexpected To %v, %v", to, err)
+	}
+	if parsed.Header.Get("Bcc") != "" {
+		t.Errorf("expected no Bcc header")
+	}
+	if parsed.Header.Get("Message-Id") != "<abc@yourdomain.com>" || parsed.Header.Get("List-Unsubscribe") == "" {
+		t.Errorf("unexpected headers %v", parsed.Header)
+	}
+	if date, err := parsed.Header.Date(); err != nil || !date.Equal(msg.Date) {
+		t.Errorf("unexpected Date %v, %v", date, err)
+	}
+	if body.ContentType != "text/plain" || body.Body != "Hallo!" {
+		t.Errorf("unexpected body %+v", body)
+	}
+}
+
+func TestMessageStructure(t *testing.T) {
+	logo := message.Attachment{Filename: "logo.png", ContentType: "image/png", Content: []byte("\x89PNG"), ContentID: "logo"}
+	report := message.Attachment{Filename: "report.pdf", ContentType: "application/pdf", Content: bytes.Repeat([]byte("%PDF"), 100)}
+
+	tests := map[string]struct {
+		msg  message.Message
+		want string
+	}{
+		"text": {
+			msg:  message.Message{Text: "Hi"},
+			want: "text/plain",
+		},
+		"html": {
+			msg:  message.Message{HTML: "<p>Hi</p>"},
+			want: "text/html",
+		},
+		"alternative": {
+			msg:  message.Message{Text: "Hi", HTML: "<p>Hi</p>"},
+			want: "multipart/alternative(text/plain, text/html)",
+		},
+		"related": {
+			msg:  message.Message{HTML: `<img src="cid:logo">`, Attachments: []message.Attachment{logo}},
+			want: "multipart/related(text/html, image/png)",
+		},
+		"mixed": {
+			msg:  message.Message{Text: "Hi", Attachments: []message.Attachment{report}},
+			want: "multipart/mixed(text/plain, application/pdf)",
+		},
+		"everything": {
+			msg:  message.Message{Text: "Hi", HTML: `<img src="cid:logo">`, Attachments: []message.Attachment{logo, report}},
+			want: "multipart/mixed(multipart/alternative(text/plain, multipart/related(text/html, image/png)), application/pdf)",
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			test.msg.From = "hello@yourdomain.com"
+			test.msg.To = []string{"user@example.com"}
+			raw, err := test.msg.Bytes()
+			if err != nil {
+				t.Fatalf("err should be nil: %s", err)
+			}
+			_, body := parse(t, raw)
+			if got := structure(body); got != test.want {
+				t.Fatalf("expected %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMessageParts(t *testing.T) {
+	text := "Hällo " + strings.Repeat("wörld ", 30) + "\n= end"
+	logo := message.Attachment{Filename: "logo.png", ContentType: "image/png", Content: bytes.Repeat([]byte{0x89, 'P', 'N', 'G'}, 50), ContentID: "logo"}
+	msg := &message.Message{
+		From:        "hello@yourdomain.com",
+		To:          []string{"user@example.com"},
+		Text:        text,
+		HTML:        `<img src="cid:logo">`,
+		Attachments: []message.Attachment{logo},
+	}
+	raw, err := msg.Bytes()
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	for _, line := range strings.Split(string(raw), "\r\n") {
+		if len(line) > 78 {
+			t.Errorf("expected lines to be at most 78 characters, got %q", line)
+		}
+	}
+
+	_, body := parse(t, raw)
+	plain, related := body.Parts[0], body.Parts[1]
+	if plain.Body != strings.ReplaceAll(text, "\n", "\r\n") {
+		t.Errorf("unexpected text body %q", plain.Body)
+	}
+	image := related.Parts[1]
+	if image.Body != string(logo.Content) {
+		t.Errorf("unexpected attachment content %q", image.Body)
+	}
+	if firstValue(image.Header, "Content-Id") != "<logo>" || !strings.HasPrefix(firstValue(image.Header, "Content-Disposition"), "inline") {
+		t.Errorf("expected an inline attachment, got %v", image.Header)
+	}
+}
+
+func TestInline(t *testing.T) {
+	a, err := ark.AttachmentFromBytes("logo.png", []byte("\x89PNG\r\n\x1a\n"))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	logo, err := message.Inline(a, "logo")
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	want := message.Attachment{Filename: "logo.png", ContentType: "image/png", Content: []byte("\x89PNG\r\n\x1a\n"), ContentID: "logo"}
+	if !reflect.DeepEqual(logo, want) {
+		t.Fatalf("expected %+v, got %+v", want, logo)
+	}
+
+	if _, err := message.Inline(a, ""); err == nil {
+		t.Errorf("expected an error without content ID")
+	}
+	a.Content = "not base64!"
+	if _, err := message.Inline(a, "logo"); err == nil {
+		t.Errorf("expected an error for content which is not base64")
+	}
+}
+
+func TestMessageParams(t *testing.T) {
+	msg := &message.Message{
+		From:    "Acme <hello@yourdomain.com>",
+		To:      []string{"Jane <user@example.com>"},
+		Cc:      []string{"cc@example.com"},
+		Bcc:     []string{"bcc@example.com"},
+		Subject: "Hi",
+		Text:    "Hi",
+	}
+	params, err := msg.Params()
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	if params.From != msg.From {
+		t.Errorf("unexpected From %q", params.From)
+	}
+	if want := []string{"user@example.com", "cc@example.com", "bcc@example.com"}; !reflect.DeepEqual(params.To, want) {
+		t.Errorf("expected recipients %v, got %v", want, params.To)
+	}
+	raw, err := base64.StdEncoding.DecodeString(params.RawMessage)
+	if err != nil {
+		t.Fatalf("expected a base64-encoded message: %s", err)
+	}
+	if _, body := parse(t, raw); body.Body != "Hi" {
+		t.Errorf("unexpected body %q", body.Body)
+	}
+	if err := params.Validate(); err != nil {
+		t.Errorf("expected params to be valid, got %s", err)
+	}
+
+	msg.Attachments = []message.Attachment{{Filename: "big.bin", Content: make([]byte, 11<<20)}}
+	if _, err := msg.Params(); !errors.Is(err, ark.ErrMessageTooLarge) {
+		t.Errorf("expected ErrMessageTooLarge, got %v", err)
+	}
+}
+
+func TestMessageInvalid(t *testing.T) {
+	msg := &message.Message{From: "hello@yourdomain.com", To: []string{"not an address"}}
+	if _, err := msg.Bytes(); err == nil {
+		t.Fatal("expected an error for an invalid address")
+	}
+	msg = &message.Message{From: "hello@yourdomain.com"}
+	if _, err := msg.Bytes(); err == nil {
+		t.Fatal("expected an error without recipients")
+	}
+	msg = &message.Message{From: "hello@yourdomain.com", To: []string{"user@example.com"}, Headers: map[string]string{"subject": "Hi"}}
+	if _, err := msg.Bytes(); err == nil {
+		t.Fatal("expected an error for a reserved header")
+	}
+}
@@ -0,0 +1,281 @@
+package option
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+)
+
+// CircuitState is the state of a circuit breaker, see [WithCircuitBreaker].
+type CircuitState int
+
+const (
+	// CircuitClosed lets requests through, counting their failures.
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails requests without sending them.
+	CircuitOpen
+	// CircuitHalfOpen lets a few probe requests through to find out whether the
+	// API has recovered.
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	default:
+		return fmt.Sprintf("CircuitState(%d)", int(s))
+	}
+}
+
+// ErrCircuitOpen is matched by [*CircuitOpenError] with [errors.Is].
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned for requests which were not sent because their
+// circuit breaker is open, or half-open with all of its probes in flight. These
+// requests are not retried.
+type CircuitOpenError struct {
+	// Key identifies the circuit breaker, see [CircuitBreakerConfig.Key].
+	Key   string
+	State CircuitState
+	// Until is the time at which the breaker becomes half-open, or zero if it is
+	// already half-open.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	if e.State == CircuitHalfOpen {
+		return fmt.Sprintf("circuit breaker for %s is half-open and waiting for its probes", e.Key)
+	}
+	return fmt.Sprintf("circuit breaker for %s is open until %s", e.Key, e.Until.Format(time.RFC3339))
+}
+
+func (e *CircuitOpenError) Is(target error) bool { return target == ErrCircuitOpen }
+
+// Retryable returns false, so that requests failing fast are not retried.
+func (e *CircuitOpenError) Retryable() bool { return false }
+
+// CircuitBreakerConfig configures [WithCircuitBreaker]. The zero value uses the
+// defaults documented on each field.
+type CircuitBreakerConfig struct {
+	// ConsecutiveFailures is the number of failures in a row which opens the
+	// breaker. It defaults to 5.
+	ConsecutiveFailures int
+	// FailureRatio opens the breaker when at least this ratio of the requests of
+	// the current Window failed, once there were at least MinRequests of them.
+	// It is disabled when 0.
+	FailureRatio float64
+	// MinRequests defaults to 10.
+	MinRequests int
+	// Window is the period over which FailureRatio is computed. It defaults to a
+	// minute.
+	Window time.Duration
+	// OpenTimeout is how long the breaker stays open before letting probes
+	// through. It defaults to 30s.
+	OpenTimeout time.Duration
+	// HalfOpenProbes is the number of probe requests allowed concurrently while
+	// half-open. The breaker closes once that many probes succeeded, and opens
+	// again as soon as one fails. It defaults to 1.
+	HalfOpenProbes int
+	// Key returns the key of the breaker of a request, so that each key has a
+	// separate breaker. It defaults to [CircuitBreakerKey], with a breaker per
+	// host and operation.
+	Key func(req *http.Request) string
+	// IsFailure reports whether an attempt failed. By default, connection errors
+	// and 5xx responses are failures.
+	IsFailure func(res *http.Response, err error) bool
+	// OnStateChange is called when the state of a breaker changes. It must not
+	// block.
+	OnStateChange func(key string, from, to CircuitState)
+	// Clock defaults to the system clock.
+	Clock Clock
+}
+
+// WithCircuitBreaker returns a RequestOption with a middleware which stops
+// sending requests to the API while it is failing, so that every caller does not
+// keep retrying against it. Once a breaker opens, its requests fail with a
+// [*CircuitOpenError] until OpenTimeout has elapsed. It then lets a few probe
+// requests through, and closes again if they succeed.
+//
+// The breakers are shared by every client and request using the returned
+// option, and are safe for concurrent use. Each attempt of a request counts
+// separately, so the breaker also stops retries.
+func WithCircuitBreaker(config CircuitBreakerConfig) RequestOption {
+	cb := newCircuitBreakers(config)
+	return WithMiddleware(cb.middleware)
+}
+
+// CircuitBreakerKey returns the default key of the breaker of a request, made
+// of its host and operation, e.g. "api.arkhq.io GET /v1/emails/{id}", where the
+// segments of the path which look like IDs are replaced by "{id}".
+func CircuitBreakerKey(req *http.Request) string {
+	return req.URL.Host + " " + operationName(req)
+}
+
+type circuitBreakers struct {
+	config   CircuitBreakerConfig
+	mu       sync.Mutex
+	breakers map[string]*circuitBreaker
+}
+
+type circuitBreaker struct {
+	state CircuitState
+	// consecutive is the number of failures in a row while closed.
+	consecutive int
+	// requests and failures are counted over the window starting at
+	// windowStart while closed.
+	windowStart             time.Time
+	requests, failures      int
+	openedAt                time.Time
+	probes, probesSucceeded int
+}
+
+func newCircuitBreakers(config CircuitBreakerConfig) *circuitBreakers {
+	if config.ConsecutiveFailures <= 0 {
+		config.ConsecutiveFailures = 5
+	}
+	if config.MinRequests <= 0 {
+		config.MinRequests = 10
+	}
+	if config.Window <= 0 {
+		config.Window = time.Minute
+	}
+	if config.OpenTimeout <= 0 {
+		config.OpenTimeout = 30 * time.Second
+	}
+	if config.HalfOpenProbes <= 0 {
+		config.HalfOpenProbes = 1
+	}
+	if config.Key == nil {
+		config.Key = CircuitBreakerKey
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = isCircuitFailure
+	}
+	if config.Clock == nil {
+		config.Clock = requestconfig.SystemClock
+	}
+	return &circuitBreakers{config: config, breakers: map[string]*circuitBreaker{}}
+}
+
+func isCircuitFailure(res *http.Response, err error) bool {
+	return err != nil || res.StatusCode >= http.StatusInternalServerError
+}
+
+func (cb *circuitBreakers) middleware(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+	key := cb.config.Key(req)
+	probe, err := cb.allow(key)
+	if err != nil {
+		return nil, err
+	}
+	res, err := next(req)
+	if errors.Is(err, context.Canceled) {
+		// The caller gave up, which says nothing about the API.
+		cb.release(key, probe)
+		return res, err
+	}
+	cb.record(key, probe, cb.config.IsFailure(res, err))
+	return res, err
+}
+
+// allow reports whether a request may be sent, and whether it is a probe.
+func (cb *circuitBreakers) allow(key string) (probe bool, err error) {
+	var from CircuitState
+	cb.mu.Lock()
+	b := cb.breakers[key]
+	if b == nil {
+		b = &circuitBreaker{}
+		cb.breakers[key] = b
+	}
+	from = b.state
+	now := cb.config.Clock.Now()
+	if b.state == CircuitOpen && !now.Before(b.openedAt.Add(cb.config.OpenTimeout)) {
+		b.state = CircuitHalfOpen
+		b.probes, b.probesSucceeded = 0, 0
+	}
+	switch {
+	case b.state == CircuitOpen:
+		err = &CircuitOpenError{Key: key, State: CircuitOpen, Until: b.openedAt.Add(cb.config.OpenTimeout)}
+	case b.state == CircuitHalfOpen && b.probes >= cb.config.HalfOpenProbes:
+		err = &CircuitOpenError{Key: key, State: CircuitHalfOpen}
+	case b.state == CircuitHalfOpen:
+		b.probes++
+		probe = true
+	}
+	to := b.state
+	cb.mu.Unlock()
+
+	cb.changed(key, from, to)
+	return probe, err
+}
+
+// record counts the outcome of a request.
+func (cb *circuitBreakers) record(key string, probe bool, failed bool) {
+	cb.mu.Lock()
+	b := cb.breakers[key]
+	from := b.state
+	now := cb.config.Clock.Now()
+	switch {
+	case probe && b.state == CircuitHalfOpen:
+		if failed {
+			b.open(now)
+		} else if b.probesSucceeded++; b.probesSucceeded >= cb.config.HalfOpenProbes {
+			b.state = CircuitClosed
+			b.reset(now)
+		}
+	case b.state == CircuitClosed:
+		if now.Sub(b.windowStart) >= cb.config.Window {
+			b.windowStart, b.requests, b.failures = now, 0, 0
+		}
+		b.requests++
+		if failed {
+			b.failures++
+			b.consecutive++
+		} else {
+			b.consecutive = 0
+		}
+		ratio := float64(b.failures) / float64(b.requests)
+		if b.consecutive >= cb.config.ConsecutiveFailures ||
+			cb.config.FailureRatio > 0 && b.requests >= cb.config.MinRequests && ratio >= cb.config.FailureRatio {
+			b.open(now)
+		}
+	}
+	to := b.state
+	cb.mu.Unlock()
+
+	cb.changed(key, from, to)
+}
+
+// release frees the slot of a probe which was canceled.
+func (cb *circuitBreakers) release(key string, probe bool) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	if b := cb.breakers[key]; probe && b.state == CircuitHalfOpen {
+		b.probes--
+	}
+}
+
+func (b *circuitBreaker) open(now time.Time) {
+	b.state = CircuitOpen
+	b.openedAt = now
+}
+
+func (b *circuitBreaker) reset(now time.Time) {
+	b.consecutive = 0
+	b.windowStart, b.requests, b.failures = now, 0, 0
+}
+
+func (cb *circuitBreakers) changed(key string, from, to CircuitState) {
+	if from != to && cb.config.OnStateChange != nil {
+		cb.config.OnStateChange(key, from, to)
+	}
+}
@@ -0,0 +1,30 @@
+package option
+
+import (
+	"context"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+	"github.com/ArkHQ-io/ark-go/internal/uuid"
+)
+
+// WithIdempotencyKeys returns a RequestOption that sets a random idempotency key
+// on requests which support one, such as [ark.EmailService.Send], unless a key
+// is given in their params. The key is generated once per call and sent with
+// every retry, so that a retried request cannot send an email twice.
+//
+// [ark.EmailService.Send]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.Send
+func WithIdempotencyKeys() RequestOption {
+	return WithIdempotencyKeyGenerator(func(context.Context) string {
+		return "ark-go-" + uuid.New()
+	})
+}
+
+// WithIdempotencyKeyGenerator is like [WithIdempotencyKeys], but the keys are
+// returned by generate, which is called once per call with its context, e.g. to
+// derive keys from the ID of a job. No key is set if generate returns "".
+func WithIdempotencyKeyGenerator(generate func(ctx context.Context) string) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.IdempotencyKey = generate
+		return nil
+	})
+}
@@ -0,0 +1,218 @@
+package option
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+)
+
+// maxLoggedBody is the length at which logged bodies are truncated.
+const maxLoggedBody = 16 << 10
+
+// redacted replaces secrets in logs.
+const redacted = "[REDACTED]"
+
+// redactedHeaders are the headers whose values are never logged.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+// WithLogger returns a RequestOption that logs each attempt of a request to
+// logger, with its method, path, status, duration, retry count and the request
+// ID returned by the API. Attempts are logged at the Info level, or at the Warn
+// level for error responses and the Error level when no response was received.
+//
+// When logger is enabled at the Debug level, the headers and bodies of requests
+// and responses are logged too. Secrets are redacted from them: the
+// Authorization header, the content of attachments, raw messages and the keys
+// of tenant credentials.
+func WithLogger(logger *slog.Logger) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.CallHooks = append(r.CallHooks, func(req *http.Request) (context.Context, func(*http.Response, error)) {
+			return context.WithValue(req.Context(), loggerCallKey{}, newTelemetryCall(req)), func(*http.Response, error) {}
+		})
+		r.Middlewares = append(r.Middlewares, func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			call, ok := req.Context().Value(loggerCallKey{}).(*telemetryCall)
+			if !ok {
+				return next(req)
+			}
+			retry := call.attempt()
+			verbose := logger.Enabled(req.Context(), slog.LevelDebug)
+			var reqBody string
+			if verbose {
+				reqBody = requestBody(req)
+			}
+
+			start := time.Now()
+			res, err := next(req)
+			duration := time.Since(start)
+
+			attrs := []slog.Attr{
+				slog.String("method", req.Method),
+				slog.String("path", req.URL.Path),
+				slog.Int("retry", retry),
+				slog.Duration("duration", duration),
+			}
+			level := slog.LevelInfo
+			switch {
+			case err != nil:
+				level = slog.LevelError
+				attrs = append(attrs, slog.String("error", err.Error()))
+			case res.StatusCode >= 400:
+				level = slog.LevelWarn
+			}
+			if res != nil {
+				attrs = append(attrs, slog.Int("status", res.StatusCode))
+				if id := call.observe(res); id != "" {
+					attrs = append(attrs, slog.String("request_id", id))
+				}
+			}
+			if verbose {
+				attrs = append(attrs, slog.Group("request",
+					slog.Any("headers", redactHeaders(req.Header)),
+					slog.String("body", reqBody),
+				))
+				if res != nil {
+					attrs = append(attrs, slog.Group("response",
+						slog.Any("headers", redactHeaders(res.Header)),
+						slog.String("body", responseBody(req, res)),
+					))
+				}
+			}
+			logger.LogAttrs(req.Context(), level, "ark request", attrs...)
+			return res, err
+		})
+		return nil
+	})
+}
+
+type loggerCallKey struct{}
+
+func redactHeaders(header http.Header) map[string]string {
+	m := make(map[string]string, len(header))
+	for key, values := range header {
+		if redactedHeaders[http.CanonicalHeaderKey(key)] {
+			m[key] = redacted
+		} else {
+			m[key] = strings.Join(values, ", ")
+		}
+	}
+	return m
+}
+
+// requestBody returns the redacted body of req, without consuming it.
+func requestBody(req *http.Request) string {
+	if req.Body == nil || req.Body == http.NoBody {
+		return ""
+	}
+	if req.GetBody == nil || req.ContentLength <= 0 {
+		// The body may only be read once, or be too long to be logged.
+		return "[streamed]"
+	}
+	if req.ContentLength > maxLoggedBody {
+		// The body would be truncated, so it is not read twice for nothing.
+		if !isJSON(req.Header) {
+			return "[non-JSON body]"
+		}
+		return "[truncated JSON body]"
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer body.Close()
+	data, _ := io.ReadAll(io.LimitReader(body, maxLoggedBody+1))
+	return redactBody(req, req.Header, data, false)
+}
+
+// responseBody returns the redacted body of res, replacing it so that the
+// caller can still read all of it.
+func responseBody(req *http.Request, res *http.Response) string {
+	if res.Body == nil || res.Body == http.NoBody {
+		return ""
+	}
+	if !isJSON(res.Header) {
+		return "[non-JSON body]"
+	}
+	data, err := io.ReadAll(io.LimitReader(res.Body, maxLoggedBody+1))
+	res.Body = multiReadCloser{io.MultiReader(bytes.NewReader(data), res.Body), res.Body}
+	if err != nil {
+		return ""
+	}
+	return redactBody(req, res.Header, data, true)
+}
+
+// multiReadCloser reads from Reader and closes Closer.
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// redactBody returns a JSON body with its secrets redacted, or a placeholder for
+// other bodies.
+func redactBody(req *http.Request, header http.Header, data []byte, response bool) string {
+	if !isJSON(header) {
+		if len(data) == 0 {
+			return ""
+		}
+		return "[non-JSON body]"
+	}
+	if len(data) > maxLoggedBody {
+		// The body cannot be parsed to be redacted.
+		return "[truncated JSON body]"
+	}
+	var v any
+	if err := json.Unmarshal(data, &v); err != nil {
+		return "[invalid JSON body]"
+	}
+	credentials := response && strings.Contains(req.URL.Path, "/credentials")
+	v = redactJSON(v, "", credentials)
+	redactedData, err := json.Marshal(v)
+	if err != nil {
+		return "[invalid JSON body]"
+	}
+	return string(redactedData)
+}
+
+// isJSON reports whether header has a JSON content type.
+func isJSON(header http.Header) bool {
+	mediaType, _, _ := mime.ParseMediaType(header.Get("Content-Type"))
+	return mediaType == "application/json"
+}
+
+// redactJSON redacts the secrets of a decoded JSON value, where parent is the
+// key of the object containing v.
+func redactJSON(v any, parent string, credentials bool) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for key, value := range v {
+			switch {
+			case key == "rawMessage",
+				key == "content" && parent == "attachments",
+				key == "key" && credentials:
+				if value != nil && value != "" {
+					v[key] = redacted
+				}
+			default:
+				v[key] = redactJSON(value, key, credentials)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			// The elements of an array are redacted as if they were the value of
+			// the key of the array.
+			v[i] = redactJSON(value, parent, credentials)
+		}
+	}
+	return v
+}
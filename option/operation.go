@@ -0,0 +1,77 @@
+package option
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/tidwall/gjson"
+)
+
+// operationName returns the method and path template of a request, e.g.
+// "GET /v1/emails/{id}", where the segments of the path which look like IDs are
+// replaced by "{id}".
+func operationName(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i, segment := range segments {
+		if !isPathWord(segment) {
+			segments[i] = "{id}"
+		}
+	}
+	return req.Method + " " + strings.Join(segments, "/")
+}
+
+// isPathWord reports whether a path segment is a fixed name, like "emails" or
+// "v1", rather than an ID.
+func isPathWord(segment string) bool {
+	if len(segment) >= 2 && segment[0] == 'v' && strings.Trim(segment[1:], "0123456789") == "" {
+		return true
+	}
+	for _, c := range segment {
+		if (c < 'a' || c > 'z') && c != '-' {
+			return false
+		}
+	}
+	return true
+}
+
+// tenantID returns the ID of the tenant of requests to tenant endpoints, or "".
+func tenantID(req *http.Request) string {
+	segments := strings.Split(req.URL.Path, "/")
+	for i := 0; i+1 < len(segments); i++ {
+		if segments[i] == "tenants" && !isPathWord(segments[i+1]) {
+			return segments[i+1]
+		}
+	}
+	return ""
+}
+
+// responseRequestID returns the ID the API gave to a request, from the
+// X-Request-Id header or the meta.requestId property of a JSON response. The
+// body of res is read and replaced, so that it can still be read by the caller.
+func responseRequestID(res *http.Response) string {
+	if res == nil {
+		return ""
+	}
+	if id := res.Header.Get("X-Request-Id"); id != "" {
+		return id
+	}
+	if mediaType, _, _ := mime.ParseMediaType(res.Header.Get("Content-Type")); mediaType != "application/json" || res.Body == nil {
+		return ""
+	}
+	body, err := io.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		// The caller gets the same error when reading the body.
+		res.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+		return ""
+	}
+	res.Body = io.NopCloser(bytes.NewReader(body))
+	return gjson.GetBytes(body, "meta.requestId").String()
+}
+
+type errReader struct{ err error }
+
+func (r errReader) Read([]byte) (int, error) { return 0, r.err }
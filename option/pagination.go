@@ -0,0 +1,31 @@
+package option
+
+import (
+	"fmt"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+)
+
+// WithPagePrefetch returns a RequestOption that makes auto-pagers, such as the one
+// returned by [ark.EmailService.ListAutoPaging], fetch up to n of the following
+// pages concurrently while the current page is being iterated over. Items are
+// still returned in order, and no more than n pages are fetched ahead of the
+// caller.
+//
+// The number of pages is taken from the first page, so items created while
+// iterating may be skipped or returned twice, just like when paging sequentially.
+// Call Close on the auto-pager when stopping before the last page.
+//
+// A value of 1 fetches pages sequentially, which is the default. Requests fail
+// with an error when n is less than 1.
+//
+// [ark.EmailService.ListAutoPaging]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailService.ListAutoPaging
+func WithPagePrefetch(n int) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		if n < 1 {
+			return fmt.Errorf("requestoption: WithPagePrefetch cannot prefetch %d pages", n)
+		}
+		r.PagePrefetch = n
+		return nil
+	})
+}
@@ -0,0 +1,138 @@
+package option
+
+import (
+	"math/rand"
+	"net/http"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+)
+
+// RetryPolicy decides whether a failed attempt to send a request is retried,
+// and how long to wait before retrying it. Requests are retried at most the
+// number of times set with [WithMaxRetries], whatever the policy.
+type RetryPolicy = requestconfig.RetryPolicy
+
+// RetryAttempt is a failed attempt to send a request, see [RetryPolicy].
+type RetryAttempt = requestconfig.RetryAttempt
+
+// Clock tells the time and waits between retries, see [WithClock].
+type Clock = requestconfig.Clock
+
+// DefaultRetryPolicy returns the policy used without [WithRetryPolicy]. It
+// retries connection errors and 408, 409, 429 and 5xx responses after an
+// exponential backoff starting at 0.5s and capped at 8s, or after the delay of
+// their Retry-After header if it is less than a minute.
+func DefaultRetryPolicy() RetryPolicy {
+	return requestconfig.DefaultRetryPolicy
+}
+
+// WithRetryPolicy returns a RequestOption that retries failed requests
+// according to policy.
+func WithRetryPolicy(policy RetryPolicy) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.RetryPolicy = policy
+		return nil
+	})
+}
+
+// WithClock returns a RequestOption that uses clock to wait between retries,
+// typically to test retries without waiting.
+func WithClock(clock Clock) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.Clock = clock
+		return nil
+	})
+}
+
+// ExponentialBackoff is a [RetryPolicy] which retries retryable attempts, see
+// [RetryAttempt.Retryable], after a random delay between 0 and an exponential
+// backoff, known as "full jitter". The backoff is Base for the first retry, and
+// doubles for each of the following ones up to Max.
+//
+// When the response has a Retry-After header, its delay is used instead, unless
+// it is longer than MaxRetryAfter.
+type ExponentialBackoff struct {
+	// Base defaults to 0.5s.
+	Base time.Duration
+	// Max defaults to 8s.
+	Max time.Duration
+	// MaxRetryAfter is the longest Retry-After delay to wait for, or 0 for no
+	// limit. Attempts asking for a longer delay are not retried.
+	MaxRetryAfter time.Duration
+	// Rand returns a random number in [0, 1). It defaults to [rand.Float64].
+	Rand func() float64
+}
+
+func (p ExponentialBackoff) RetryDelay(a RetryAttempt) (time.Duration, bool) {
+	if !a.Retryable() {
+		return 0, false
+	}
+	if delay, ok := a.RetryAfter(); ok {
+		if p.MaxRetryAfter > 0 && delay > p.MaxRetryAfter {
+			return 0, false
+		}
+		return delay, true
+	}
+
+	backoff, maxDelay := p.Base, p.Max
+	if backoff <= 0 {
+		backoff = 500 * time.Millisecond
+	}
+	if maxDelay <= 0 {
+		maxDelay = 8 * time.Second
+	}
+	for i := 1; i < a.Attempt && backoff < maxDelay; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, maxDelay)
+	random := p.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	return time.Duration(random() * float64(backoff)), true
+}
+
+// FixedBackoff is a [RetryPolicy] which retries retryable attempts, see
+// [RetryAttempt.Retryable], after Delay, or after the delay of their
+// Retry-After header if it is longer.
+type FixedBackoff struct {
+	Delay time.Duration
+}
+
+func (p FixedBackoff) RetryDelay(a RetryAttempt) (time.Duration, bool) {
+	if !a.Retryable() {
+		return 0, false
+	}
+	if delay, ok := a.RetryAfter(); ok && delay > p.Delay {
+		return delay, true
+	}
+	return p.Delay, true
+}
+
+// NeverRetryNonIdempotent returns a [RetryPolicy] which never retries requests
+// that could have side effects twice, i.e. POST and PATCH requests without an
+// Idempotency-Key header, and otherwise defers to policy. If policy is nil, the
+// [DefaultRetryPolicy] is used.
+//
+// See [WithIdempotencyKeys] to send emails with idempotency keys.
+func NeverRetryNonIdempotent(policy RetryPolicy) RetryPolicy {
+	if policy == nil {
+		policy = DefaultRetryPolicy()
+	}
+	return nonIdempotentPolicy{policy}
+}
+
+type nonIdempotentPolicy struct {
+	policy RetryPolicy
+}
+
+func (p nonIdempotentPolicy) RetryDelay(a RetryAttempt) (time.Duration, bool) {
+	switch a.Request.Method {
+	case http.MethodPost, http.MethodPatch:
+		if a.Request.Header.Get("Idempotency-Key") == "" {
+			return 0, false
+		}
+	}
+	return p.policy.RetryDelay(a)
+}
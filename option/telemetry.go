@@ -0,0 +1,162 @@
+package option
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+	"github.com/ArkHQ-io/ark-go/telemetry"
+)
+
+// WithTracer returns a RequestOption that traces requests with tracer. Each
+// request gets a span named after its operation, e.g. "GET /v1/emails/{id}",
+// with a child "ark.attempt" span for each attempt, including retries. The
+// spans have the attributes defined in the telemetry package: the operation,
+// tenant ID, status code, retry count and request ID.
+func WithTracer(tracer telemetry.Tracer) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.CallHooks = append(r.CallHooks, func(req *http.Request) (context.Context, func(*http.Response, error)) {
+			call := newTelemetryCall(req)
+			ctx, span := tracer.Start(req.Context(), call.operation, call.attrs...)
+			return context.WithValue(ctx, tracerCallKey{}, call), func(res *http.Response, err error) {
+				span.SetAttributes(call.resultAttrs(res)...)
+				if err != nil {
+					span.RecordError(err)
+				}
+				span.End()
+			}
+		})
+		r.Middlewares = append(r.Middlewares, func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			call, ok := req.Context().Value(tracerCallKey{}).(*telemetryCall)
+			if !ok {
+				return next(req)
+			}
+			retry := call.attempt()
+			ctx, span := tracer.Start(req.Context(), "ark.attempt", append(call.attrs, telemetry.Int(telemetry.AttrRetryCount, retry))...)
+			defer span.End()
+			res, err := next(req.WithContext(ctx))
+			requestID := call.observe(res)
+			span.SetAttributes(statusAttrs(res, requestID)...)
+			if err != nil {
+				span.RecordError(err)
+			}
+			return res, err
+		})
+		return nil
+	})
+}
+
+// WithMeter returns a RequestOption that records metrics of requests with
+// meter: the number of requests and retries, and the duration of requests and
+// of their attempts. The metrics are named and have the attributes defined in
+// the telemetry package.
+func WithMeter(meter telemetry.Meter) RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.CallHooks = append(r.CallHooks, func(req *http.Request) (context.Context, func(*http.Response, error)) {
+			call := newTelemetryCall(req)
+			ctx := req.Context()
+			return context.WithValue(ctx, meterCallKey{}, call), func(res *http.Response, err error) {
+				attrs := append(call.attrs, call.resultAttrs(res)...)
+				meter.Add(ctx, telemetry.MetricRequests, 1, attrs...)
+				meter.Record(ctx, telemetry.MetricRequestDuration, time.Since(call.start).Seconds(), attrs...)
+				if retries := call.retries(); retries > 0 {
+					meter.Add(ctx, telemetry.MetricRetries, int64(retries), call.attrs...)
+				}
+			}
+		})
+		r.Middlewares = append(r.Middlewares, func(req *http.Request, next MiddlewareNext) (*http.Response, error) {
+			call, ok := req.Context().Value(meterCallKey{}).(*telemetryCall)
+			if !ok {
+				return next(req)
+			}
+			retry := call.attempt()
+			start := time.Now()
+			res, err := next(req)
+			attrs := append(call.attrs, telemetry.Int(telemetry.AttrRetryCount, retry))
+			attrs = append(attrs, statusAttrs(res, "")...)
+			meter.Record(req.Context(), telemetry.MetricAttemptDuration, time.Since(start).Seconds(), attrs...)
+			return res, err
+		})
+		return nil
+	})
+}
+
+type tracerCallKey struct{}
+
+type meterCallKey struct{}
+
+// telemetryCall is the state of a request shared by its attempts.
+type telemetryCall struct {
+	start     time.Time
+	operation string
+	// attrs describe the request, before it is sent.
+	attrs []telemetry.Attribute
+
+	mu        sync.Mutex
+	attempts  int
+	requestID string
+}
+
+func newTelemetryCall(req *http.Request) *telemetryCall {
+	call := &telemetryCall{
+		start:     time.Now(),
+		operation: operationName(req),
+	}
+	call.attrs = []telemetry.Attribute{
+		telemetry.String(telemetry.AttrOperation, call.operation),
+		telemetry.String(telemetry.AttrMethod, req.Method),
+		telemetry.String(telemetry.AttrHost, req.URL.Host),
+	}
+	if id := tenantID(req); id != "" {
+		call.attrs = append(call.attrs, telemetry.String(telemetry.AttrTenantID, id))
+	}
+	// The attributes are appended to for each span and measurement, which must
+	// not share their backing array.
+	call.attrs = call.attrs[:len(call.attrs):len(call.attrs)]
+	return call
+}
+
+// attempt counts an attempt, and returns its retry number.
+func (c *telemetryCall) attempt() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.attempts++
+	return c.attempts - 1
+}
+
+func (c *telemetryCall) retries() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return max(c.attempts-1, 0)
+}
+
+// observe records the request ID of the response to an attempt, and returns it.
+func (c *telemetryCall) observe(res *http.Response) string {
+	id := responseRequestID(res)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requestID = id
+	return id
+}
+
+// resultAttrs describe the outcome of the request, with its final response.
+func (c *telemetryCall) resultAttrs(res *http.Response) []telemetry.Attribute {
+	c.mu.Lock()
+	requestID := c.requestID
+	c.mu.Unlock()
+	attrs := []telemetry.Attribute{telemetry.Int(telemetry.AttrRetryCount, c.retries())}
+	return append(attrs, statusAttrs(res, requestID)...)
+}
+
+func statusAttrs(res *http.Response, requestID string) []telemetry.Attribute {
+	var attrs []telemetry.Attribute
+	if res != nil {
+		attrs = append(attrs, telemetry.Int(telemetry.AttrStatusCode, res.StatusCode))
+	}
+	if requestID != "" {
+		attrs = append(attrs, telemetry.String(telemetry.AttrRequestID, requestID))
+	}
+	return attrs
+}
@@ -0,0 +1,17 @@
+package option
+
+import "github.com/ArkHQ-io/ark-go/internal/requestconfig"
+
+// WithValidation returns a RequestOption that validates the params of a request
+// before sending it, for params with a Validate method such as
+// [ark.EmailSendParams.Validate]. Invalid params are returned as a
+// [*ark.ValidationError] without making a request.
+//
+// [ark.EmailSendParams.Validate]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#EmailSendParams.Validate
+// [*ark.ValidationError]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#ValidationError
+func WithValidation() RequestOption {
+	return requestconfig.RequestOptionFunc(func(r *requestconfig.RequestConfig) error {
+		r.ValidateParams = true
+		return nil
+	})
+}
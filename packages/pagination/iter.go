@@ -0,0 +1,61 @@
+//go:build go1.23
+
+package pagination
+
+import "iter"
+
+// All returns an iterator over the remaining items of every page, for use with
+// range-over-func:
+//
+//	for email, err := range iter.All() {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(email.ID)
+//	}
+//
+// Pages are fetched lazily, so breaking out of the loop stops any further
+// requests, including pages being prefetched in the background. If a page
+// cannot be fetched, the iterator yields the error with the zero value of T and
+// stops.
+func (r *PageNumberPaginationAutoPager[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		defer r.Close()
+		for r.Next() {
+			if !yield(r.Current(), nil) {
+				return
+			}
+		}
+		if err := r.Err(); err != nil {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// Pages returns an iterator over whole pages, starting with the current one.
+// Like [PageNumberPaginationAutoPager.All], pages are fetched lazily and a failed
+// fetch is yielded as a nil page with the error.
+//
+// Pages should not be combined with [PageNumberPaginationAutoPager.Next] on the
+// same auto-pager.
+func (r *PageNumberPaginationAutoPager[T]) Pages() iter.Seq2[*PageNumberPagination[T], error] {
+	return func(yield func(*PageNumberPagination[T], error) bool) {
+		defer r.Close()
+		if r.err != nil {
+			yield(nil, r.err)
+			return
+		}
+		for r.page != nil && len(r.page.Data) > 0 {
+			if !yield(r.page, nil) {
+				return
+			}
+			r.idx = 0
+			r.page, r.err = r.nextPage()
+			if r.err != nil {
+				yield(nil, r.err)
+				return
+			}
+		}
+	}
+}
@@ -66,7 +66,20 @@ func (r *PageNumberPagination[T]) UnmarshalJSON(data []byte) error {
 // GetNextPage returns the next page as defined by this pagination style. When
 // there is no next page, this function will return a 'nil' for the page value, but
 // will not return an error
+//
+// The next page is fetched with the context of the request which returned this
+// page, see [PageNumberPagination.GetNextPageWithContext] to use a different one.
 func (r *PageNumberPagination[T]) GetNextPage() (res *PageNumberPagination[T], err error) {
+	ctx := context.Background()
+	if r.cfg != nil && r.cfg.Context != nil {
+		ctx = r.cfg.Context
+	}
+	return r.GetNextPageWithContext(ctx)
+}
+
+// GetNextPageWithContext is like [PageNumberPagination.GetNextPage], but fetches
+// the next page with the given context.
+func (r *PageNumberPagination[T]) GetNextPageWithContext(ctx context.Context) (res *PageNumberPagination[T], err error) {
 	if len(r.Data) == 0 {
 		return nil, nil
 	}
@@ -74,9 +87,14 @@ func (r *PageNumberPagination[T]) GetNextPage() (res *PageNumberPagination[T], e
 	if currentPage >= r.TotalPages {
 		return nil, nil
 	}
-	cfg := r.cfg.Clone(context.Background())
+	return r.getPage(ctx, currentPage+1)
+}
+
+// getPage fetches the given page with the same request as this page.
+func (r *PageNumberPagination[T]) getPage(ctx context.Context, page int64) (res *PageNumberPagination[T], err error) {
+	cfg := r.cfg.Clone(ctx)
 	query := cfg.Request.URL.Query()
-	query.Set("page", fmt.Sprintf("%d", currentPage+1))
+	query.Set("page", fmt.Sprintf("%d", page))
 	cfg.Request.URL.RawQuery = query.Encode()
 	var raw *http.Response
 	cfg.ResponseInto = &raw
@@ -98,19 +116,29 @@ func (r *PageNumberPagination[T]) SetPageConfig(cfg *requestconfig.RequestConfig
 }
 
 type PageNumberPaginationAutoPager[T any] struct {
-	page *PageNumberPagination[T]
-	cur  T
-	idx  int
-	run  int
-	err  error
+	page     *PageNumberPagination[T]
+	cur      T
+	idx      int
+	run      int
+	err      error
+	prefetch *prefetcher[T]
 	paramObj
 }
 
+// NewPageNumberPaginationAutoPager returns an auto-pager starting at page. If
+// page was requested with [option.WithPagePrefetch], the following pages are
+// fetched concurrently in the background.
+//
+// [option.WithPagePrefetch]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go/option#WithPagePrefetch
 func NewPageNumberPaginationAutoPager[T any](page *PageNumberPagination[T], err error) *PageNumberPaginationAutoPager[T] {
-	return &PageNumberPaginationAutoPager[T]{
+	r := &PageNumberPaginationAutoPager[T]{
 		page: page,
 		err:  err,
 	}
+	if err == nil && page != nil && page.cfg != nil && page.cfg.PagePrefetch > 1 && len(page.Data) > 0 && page.Page < page.TotalPages {
+		r.prefetch = newPrefetcher(page, page.cfg.PagePrefetch)
+	}
+	return r
 }
 
 func (r *PageNumberPaginationAutoPager[T]) Next() bool {
@@ -119,8 +147,10 @@ func (r *PageNumberPaginationAutoPager[T]) Next() bool {
 	}
 	if r.idx >= len(r.page.Data) {
 		r.idx = 0
-		r.page, r.err = r.page.GetNextPage()
+		r.page, r.err = r.nextPage()
 		if r.err != nil || r.page == nil || len(r.page.Data) == 0 {
+			// Stop fetching the following pages, which will not be iterated.
+			r.Close()
 			return false
 		}
 	}
@@ -130,6 +160,22 @@ func (r *PageNumberPaginationAutoPager[T]) Next() bool {
 	return true
 }
 
+func (r *PageNumberPaginationAutoPager[T]) nextPage() (*PageNumberPagination[T], error) {
+	if r.prefetch != nil {
+		return r.prefetch.next()
+	}
+	return r.page.GetNextPage()
+}
+
+// Close stops fetching pages in the background. It only needs to be called when
+// iteration is abandoned while Next still returns true, as prefetching stops as
+// soon as Next returns false, and is a no-op when prefetching is disabled.
+func (r *PageNumberPaginationAutoPager[T]) Close() {
+	if r.prefetch != nil {
+		r.prefetch.cancel()
+	}
+}
+
 func (r *PageNumberPaginationAutoPager[T]) Current() T {
 	return r.cur
 }
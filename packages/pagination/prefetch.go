@@ -0,0 +1,79 @@
+package pagination
+
+import "context"
+
+type prefetchResult[T any] struct {
+	page *PageNumberPagination[T]
+	err  error
+}
+
+// prefetcher fetches the pages following a first page concurrently, and hands
+// them out in order. At most concurrency pages are buffered ahead of the
+// consumer, and at most concurrency requests are in flight, at any time.
+type prefetcher[T any] struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	// pending holds the results of started fetches, in page order.
+	pending chan chan prefetchResult[T]
+	// sem bounds the number of requests in flight.
+	sem chan struct{}
+}
+
+func newPrefetcher[T any](first *PageNumberPagination[T], concurrency int) *prefetcher[T] {
+	ctx := first.cfg.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	p := &prefetcher[T]{
+		ctx:     ctx,
+		cancel:  cancel,
+		pending: make(chan chan prefetchResult[T], concurrency),
+		sem:     make(chan struct{}, concurrency),
+	}
+	go p.run(first)
+	return p
+}
+
+// run starts a fetch for each page once there is room for it in pending, and
+// fewer than concurrency requests are in flight. Fetches are started in page
+// order, so that the page the consumer waits for is never left behind. The
+// total number of pages is taken from the first page.
+func (p *prefetcher[T]) run(first *PageNumberPagination[T]) {
+	defer close(p.pending)
+	for n := first.Page + 1; n <= first.TotalPages; n++ {
+		result := make(chan prefetchResult[T], 1)
+		select {
+		case p.pending <- result:
+		case <-p.ctx.Done():
+			return
+		}
+		select {
+		case p.sem <- struct{}{}:
+		case <-p.ctx.Done():
+			result <- prefetchResult[T]{nil, p.ctx.Err()}
+			return
+		}
+		go func(n int64) {
+			page, err := first.getPage(p.ctx, n)
+			<-p.sem
+			result <- prefetchResult[T]{page, err}
+		}(n)
+	}
+}
+
+// next returns the next page, waiting for it to be fetched if needed. It
+// returns a nil page once every page has been handed out.
+func (p *prefetcher[T]) next() (*PageNumberPagination[T], error) {
+	result, ok := <-p.pending
+	if !ok {
+		err := p.ctx.Err()
+		p.cancel()
+		return nil, err
+	}
+	res := <-result
+	if res.err != nil {
+		p.cancel()
+	}
+	return res.page, res.err
+}
@@ -0,0 +1,251 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// pagedAPI serves totalPages pages of emails with perPage emails each and
+// records the pages which were requested.
+type pagedAPI struct {
+	totalPages int
+	perPage    int
+	// failPage, if non-zero, is answered with a 500 Internal Server Error.
+	failPage int
+	// emptyPage, if non-zero, is served without any email.
+	emptyPage int
+	// onRequest, if set, is called before each page is served.
+	onRequest func(req *http.Request, page int)
+
+	mu        sync.Mutex
+	requested []int
+}
+
+func (p *pagedAPI) serve(req *http.Request) (*http.Response, error) {
+	page := 1
+	if v := req.URL.Query().Get("page"); v != "" {
+		page, _ = strconv.Atoi(v)
+	}
+	p.mu.Lock()
+	p.requested = append(p.requested, page)
+	p.mu.Unlock()
+	if p.onRequest != nil {
+		p.onRequest(req, page)
+	}
+	if err := req.Context().Err(); err != nil {
+		return nil, err
+	}
+	if page == p.failPage {
+		return jsonResponse(http.StatusInternalServerError, `{}`), nil
+	}
+
+	data := make([]string, 0, p.perPage)
+	if page <= p.totalPages && page != p.emptyPage {
+		for i := 0; i < p.perPage; i++ {
+			data = append(data, fmt.Sprintf(`{"id":"msg_%d_%d","from":"hello@yourdomain.com","status":"sent"}`, page, i))
+		}
+	}
+	body := fmt.Sprintf(
+		`{"data":[%s],"page":%d,"perPage":%d,"total":%d,"totalPages":%d}`,
+		strings.Join(data, ","), page, p.perPage, p.totalPages*p.perPage, p.totalPages,
+	)
+	return jsonResponse(http.StatusOK, body), nil
+}
+
+func (p *pagedAPI) Requested() []int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]int(nil), p.requested...)
+}
+
+func TestAutoPagingUsesCallerContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	api := &pagedAPI{totalPages: 3, perPage: 2}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	iter := client.Emails.ListAutoPaging(ctx, ark.EmailListParams{})
+	count := 0
+	for iter.Next() {
+		count++
+		if count == 2 {
+			cancel()
+		}
+	}
+	if !errors.Is(iter.Err(), context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", iter.Err())
+	}
+	if count != 2 {
+		t.Fatalf("expected to stop after the first page, got %d emails", count)
+	}
+}
+
+func TestGetNextPageWithContext(t *testing.T) {
+	type key struct{}
+	var got any
+	api := &pagedAPI{totalPages: 2, perPage: 1}
+	api.onRequest = func(req *http.Request, page int) {
+		if page == 2 {
+			got = req.Context().Value(key{})
+		}
+	}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	page, err := client.Emails.List(context.Background(), ark.EmailListParams{})
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	ctx := context.WithValue(context.Background(), key{}, "next")
+	next, err := page.GetNextPageWithContext(ctx)
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if next == nil || next.Page != 2 || next.Data[0].ID != "msg_2_0" {
+		t.Fatalf("unexpected next page %+v", next)
+	}
+	if got != "next" {
+		t.Fatalf("expected the next page to be fetched with the given context, got %v", got)
+	}
+
+	last, err := next.GetNextPageWithContext(ctx)
+	if err != nil || last != nil {
+		t.Fatalf("expected no page after the last one, got %+v, %v", last, err)
+	}
+}
+
+func TestAutoPagingPrefetch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	api := &pagedAPI{totalPages: 10, perPage: 3}
+	api.onRequest = func(req *http.Request, page int) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			max := maxInFlight.Load()
+			if n <= max || maxInFlight.CompareAndSwap(max, n) {
+				break
+			}
+		}
+		// Later pages finish first, to check that items are still returned in order.
+		time.Sleep(time.Duration(20-page) * time.Millisecond)
+	}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithPagePrefetch(4))
+	var ids []string
+	for iter.Next() {
+		ids = append(ids, iter.Current().ID)
+	}
+	if iter.Err() != nil {
+		t.Fatalf("err should be nil: %s", iter.Err().Error())
+	}
+
+	var want []string
+	for page := 1; page <= 10; page++ {
+		for i := 0; i < 3; i++ {
+			want = append(want, fmt.Sprintf("msg_%d_%d", page, i))
+		}
+	}
+	if !reflect.DeepEqual(ids, want) {
+		t.Fatalf("expected %v, got %v", want, ids)
+	}
+	if max := maxInFlight.Load(); max > 4 || max < 2 {
+		t.Fatalf("expected between 2 and 4 concurrent requests, got %d", max)
+	}
+}
+
+func TestAutoPagingPrefetchBackpressure(t *testing.T) {
+	api := &pagedAPI{totalPages: 20, perPage: 1}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithPagePrefetch(3))
+	defer iter.Close()
+	if !iter.Next() {
+		t.Fatalf("expected a first email, got %v", iter.Err())
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	// The first page, and three pages fetched ahead of it.
+	if requested := api.Requested(); len(requested) != 4 {
+		t.Fatalf("expected 4 pages to be requested, got %v", requested)
+	}
+}
+
+func TestAutoPagingPrefetchError(t *testing.T) {
+	api := &pagedAPI{totalPages: 6, perPage: 2, failPage: 3}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithPagePrefetch(2))
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	var apierr *ark.Error
+	if !errors.As(iter.Err(), &apierr) || apierr.StatusCode != http.StatusInternalServerError {
+		t.Fatalf("expected a 500 error, got %v", iter.Err())
+	}
+	if count != 4 {
+		t.Fatalf("expected the emails of the first 2 pages, got %d", count)
+	}
+}
+
+func TestAutoPagingPrefetchStopsOnEmptyPage(t *testing.T) {
+	started, canceled := make(chan int, 10), make(chan int, 10)
+	api := &pagedAPI{totalPages: 10, perPage: 1, emptyPage: 2}
+	api.onRequest = func(req *http.Request, page int) {
+		switch {
+		case page == 2:
+			// The empty page is served once pages 3 and 4 are being fetched.
+			<-started
+			<-started
+		case page > 2:
+			started <- page
+			<-req.Context().Done()
+			canceled <- page
+		}
+	}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	// Iteration stops at the empty page without calling Close, which must stop
+	// the fetches of the following pages.
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithPagePrefetch(3))
+	count := 0
+	for iter.Next() {
+		count++
+	}
+	if iter.Err() != nil || count != 1 {
+		t.Fatalf("expected to stop after the first email, got %d, %v", count, iter.Err())
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatalf("expected the fetches of pages 3 and 4 to be canceled")
+		}
+	}
+}
+
+func TestAutoPagingPrefetchInvalid(t *testing.T) {
+	api := &pagedAPI{totalPages: 2, perPage: 1}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithPagePrefetch(0))
+	if iter.Next() || iter.Err() == nil {
+		t.Fatalf("expected an error, got %v", iter.Err())
+	}
+	if requested := api.Requested(); len(requested) != 0 {
+		t.Fatalf("expected no request, got %v", requested)
+	}
+}
@@ -0,0 +1,208 @@
+package ark
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/internal/requestconfig"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// RateLimiter is a token bucket which delays requests so that they stay within
+// the rate limit of the API, instead of failing with a 429 and being retried.
+// It is safe for concurrent use, and a single RateLimiter should be shared by
+// every client using the same API key.
+//
+// A new RateLimiter does not delay requests until it learns the limit, either
+// from [RateLimiter.Seed], from [RateLimiter.Update], or from the
+// X-RateLimit-Limit, X-RateLimit-Remaining and X-RateLimit-Reset headers of the
+// responses it sees. A 429 response also pauses requests for its Retry-After
+// delay.
+//
+//	limiter := ark.NewRateLimiter()
+//	client := ark.NewClient(option.WithMiddleware(limiter.Middleware()))
+//	if err := limiter.Seed(ctx, &client.Limits); err != nil {
+//		return err
+//	}
+type RateLimiter struct {
+	clock option.Clock
+	mu    sync.Mutex
+	// rate is the number of tokens added per second, or 0 if the limit is not
+	// known yet. The API only has limits per second.
+	rate  float64
+	burst float64
+	// tokens may be negative when requests are waiting for tokens.
+	tokens float64
+	// last is the time tokens were last added.
+	last time.Time
+	// paused is the time until which no tokens are added, after the API
+	// reported that no requests remain.
+	paused time.Time
+}
+
+// NewRateLimiter returns a RateLimiter which does not delay requests until it
+// learns the rate limit.
+func NewRateLimiter() *RateLimiter {
+	return NewRateLimiterWithClock(requestconfig.SystemClock)
+}
+
+// NewRateLimiterWithClock is like [NewRateLimiter], but tells the time and waits
+// with clock, typically to test rate limiting without waiting. See
+// [option.WithClock].
+func NewRateLimiterWithClock(clock option.Clock) *RateLimiter {
+	return &RateLimiter{clock: clock}
+}
+
+// Seed fetches the rate limit of the account with [LimitService.Get] and
+// updates the limiter with it.
+func (l *RateLimiter) Seed(ctx context.Context, limits *LimitService, opts ...option.RequestOption) error {
+	res, err := limits.Get(ctx, opts...)
+	if err != nil {
+		return err
+	}
+	l.Update(res.Data.RateLimit)
+	return nil
+}
+
+// Update sets the limit and the number of remaining requests of the limiter to
+// the ones reported by the API.
+func (l *RateLimiter) Update(limit LimitsDataRateLimit) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	l.setLimit(now, limit.Limit)
+	l.setRemaining(now, limit.Remaining, time.Unix(limit.Reset, 0))
+}
+
+// Middleware returns a middleware which waits for the limiter before sending
+// each request, including retries, and updates it from the responses.
+func (l *RateLimiter) Middleware() option.Middleware {
+	return func(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+		if err := l.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		res, err := next(req)
+		if res != nil {
+			l.observe(res)
+		}
+		return res, err
+	}
+}
+
+// Wait blocks until a request can be sent without exceeding the rate limit. It
+// returns an error without waiting if ctx is done, or would be done before the
+// request can be sent.
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	now := l.clock.Now()
+	l.mu.Lock()
+	delay := l.reserve(now)
+	l.mu.Unlock()
+	if delay <= 0 {
+		return nil
+	}
+
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(now.Add(delay)) {
+		l.cancel()
+		return fmt.Errorf("ark: waiting %s for the rate limit would exceed the context deadline: %w", delay, context.DeadlineExceeded)
+	}
+	select {
+	case <-l.clock.After(delay):
+		return nil
+	case <-ctx.Done():
+		l.cancel()
+		return ctx.Err()
+	}
+}
+
+// reserve takes a token and returns how long to wait until it is available.
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	if l.rate == 0 {
+		return 0
+	}
+	l.advance(now)
+	l.tokens--
+	start := now
+	if l.paused.After(now) {
+		start = l.paused
+	}
+	if l.tokens >= 0 {
+		return start.Sub(now)
+	}
+	return start.Sub(now) + time.Duration(-l.tokens/l.rate*float64(time.Second))
+}
+
+// cancel returns the token taken by a request which stopped waiting.
+func (l *RateLimiter) cancel() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.rate > 0 {
+		l.tokens++
+	}
+}
+
+// advance adds the tokens accumulated since the last call, except while paused.
+func (l *RateLimiter) advance(now time.Time) {
+	from := l.last
+	if l.paused.After(from) {
+		from = l.paused
+	}
+	if now.After(from) {
+		l.tokens = min(l.burst, l.tokens+l.rate*now.Sub(from).Seconds())
+	}
+	if now.After(l.last) {
+		l.last = now
+	}
+}
+
+func (l *RateLimiter) setLimit(now time.Time, limit int64) {
+	if limit <= 0 {
+		return
+	}
+	if l.rate == 0 {
+		// The limit was unknown, so the bucket starts full.
+		l.tokens = float64(limit)
+		l.last = now
+	}
+	l.burst = float64(limit)
+	l.rate = float64(limit)
+}
+
+// setRemaining lowers the tokens to the number of requests the API reports as
+// remaining, pausing until reset if there are none.
+func (l *RateLimiter) setRemaining(now time.Time, remaining int64, reset time.Time) {
+	if l.rate == 0 {
+		return
+	}
+	l.advance(now)
+	l.tokens = min(l.tokens, float64(remaining))
+	if remaining <= 0 && reset.After(now) && reset.After(l.paused) {
+		l.paused = reset
+	}
+}
+
+// observe updates the limiter from the rate limit headers of res, and pauses it
+// for the Retry-After delay of 429 responses.
+func (l *RateLimiter) observe(res *http.Response) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.clock.Now()
+	if limit, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Limit"), 10, 64); err == nil {
+		l.setLimit(now, limit)
+	}
+	if remaining, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Remaining"), 10, 64); err == nil {
+		reset := time.Time{}
+		if unix, err := strconv.ParseInt(res.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			reset = time.Unix(unix, 0)
+		}
+		l.setRemaining(now, remaining, reset)
+	}
+	if res.StatusCode == http.StatusTooManyRequests && l.rate > 0 {
+		if seconds, err := strconv.ParseFloat(res.Header.Get("Retry-After"), 64); err == nil && seconds > 0 {
+			l.setRemaining(now, 0, now.Add(time.Duration(seconds*float64(time.Second))))
+		}
+	}
+}
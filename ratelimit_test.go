@@ -0,0 +1,134 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+// serveRateLimited answers requests to the /limits endpoint with a rate limit
+// of limit requests per second. Other requests are counted in requests and
+// answered with headers.
+func serveRateLimited(limit int, requests *atomic.Int64, headers http.Header) func(req *http.Request) (*http.Response, error) {
+	return func(req *http.Request) (*http.Response, error) {
+		body := `{}`
+		if strings.HasSuffix(req.URL.Path, "/limits") {
+			body = fmt.Sprintf(`{"data":{"rateLimit":{"limit":%d,"period":"second","remaining":%d,"reset":%d}}}`, limit, limit, time.Now().Unix()+1)
+		} else {
+			requests.Add(1)
+		}
+		res := jsonResponse(http.StatusOK, body)
+		for key, values := range headers {
+			res.Header[key] = values
+		}
+		return res, nil
+	}
+}
+
+func TestRateLimiterSeed(t *testing.T) {
+	clock := newFakeClock()
+	limiter := ark.NewRateLimiterWithClock(clock)
+	var requests atomic.Int64
+	client := newTestClient(serveRateLimited(50, &requests, nil), option.WithMaxRetries(0), option.WithMiddleware(limiter.Middleware()))
+	if err := limiter.Seed(context.Background(), &client.Limits); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	for i := 0; i < 60; i++ {
+		if _, err := client.Logs.Get(context.Background(), "req_123"); err != nil {
+			t.Fatalf("err should be nil: %s", err.Error())
+		}
+	}
+	// The first 50 requests use the full bucket, the other 10 wait 20ms each.
+	want := make([]time.Duration, 10)
+	for i := range want {
+		want[i] = 20 * time.Millisecond
+	}
+	if !reflect.DeepEqual(clock.Waits(), want) {
+		t.Fatalf("expected waits %v, got %v", want, clock.Waits())
+	}
+	if requests.Load() != 60 {
+		t.Fatalf("expected 60 requests, got %d", requests.Load())
+	}
+}
+
+func TestRateLimiterConcurrent(t *testing.T) {
+	// The clock is frozen, so that each request waits for the tokens taken by
+	// the requests before it.
+	clock := newFakeClock()
+	clock.frozen = true
+	limiter := ark.NewRateLimiterWithClock(clock)
+	limiter.Update(ark.LimitsDataRateLimit{Limit: 20, Period: "second", Remaining: 20, Reset: clock.Now().Unix() + 1})
+	var requests atomic.Int64
+	client := newTestClient(serveRateLimited(20, &requests, nil), option.WithMaxRetries(0), option.WithMiddleware(limiter.Middleware()))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 30; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			var err error
+			if i%2 == 0 {
+				_, err = client.Logs.Get(context.Background(), "req_123")
+			} else {
+				_, err = client.Emails.Get(context.Background(), "msg_123", ark.EmailGetParams{})
+			}
+			if err != nil {
+				t.Errorf("err should be nil: %s", err.Error())
+			}
+		}(i)
+	}
+	wg.Wait()
+	// The last 10 requests wait 50ms more than each other, whichever service
+	// sends them.
+	waits := clock.Waits()
+	slices.Sort(waits)
+	var want []time.Duration
+	for i := 1; i <= 10; i++ {
+		want = append(want, time.Duration(i)*50*time.Millisecond)
+	}
+	if !reflect.DeepEqual(waits, want) {
+		t.Fatalf("expected waits %v, got %v", want, waits)
+	}
+}
+
+func TestRateLimiterHeaders(t *testing.T) {
+	clock := &fakeClock{now: time.Now()}
+	limiter := ark.NewRateLimiterWithClock(clock)
+	var requests atomic.Int64
+	headers := http.Header{
+		"X-Ratelimit-Limit":     []string{"10"},
+		"X-Ratelimit-Remaining": []string{"0"},
+		"X-Ratelimit-Reset":     []string{strconv.FormatInt(clock.Now().Unix()+2, 10)},
+	}
+	client := newTestClient(serveRateLimited(10, &requests, headers), option.WithMaxRetries(0), option.WithMiddleware(limiter.Middleware()))
+
+	if _, err := client.Logs.Get(context.Background(), "req_123"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := client.Logs.Get(ctx, "req_123")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the request to wait past its deadline, got %v", err)
+	}
+	if len(clock.Waits()) != 0 {
+		t.Fatalf("expected the request to fail without waiting, waited %v", clock.Waits())
+	}
+	if requests.Load() != 1 {
+		t.Fatalf("expected the request not to be sent, got %d requests", requests.Load())
+	}
+}
@@ -0,0 +1,191 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+)
+
+func retryAttempt(method string, status int, header http.Header, attempt int) option.RetryAttempt {
+	req, _ := http.NewRequest(method, "https://api.arkhq.io/v1/emails", nil)
+	a := option.RetryAttempt{Request: req, Attempt: attempt, Now: newFakeClock().Now()}
+	if status != 0 {
+		a.Response = &http.Response{StatusCode: status, Header: header}
+	}
+	return a
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	policy := option.ExponentialBackoff{
+		Base: time.Second,
+		Max:  10 * time.Second,
+		Rand: func() float64 { return 0.5 },
+	}
+	var delays []time.Duration
+	for attempt := 1; attempt <= 6; attempt++ {
+		delay, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusServiceUnavailable, nil, attempt))
+		if !retry {
+			t.Fatalf("expected attempt %d to be retried", attempt)
+		}
+		delays = append(delays, delay)
+	}
+	want := []time.Duration{500 * time.Millisecond, time.Second, 2 * time.Second, 4 * time.Second, 5 * time.Second, 5 * time.Second}
+	if !reflect.DeepEqual(delays, want) {
+		t.Fatalf("expected delays %v, got %v", want, delays)
+	}
+
+	if _, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusBadRequest, nil, 1)); retry {
+		t.Fatalf("expected a 400 not to be retried")
+	}
+	if _, retry := policy.RetryDelay(retryAttempt(http.MethodGet, 0, nil, 1)); !retry {
+		t.Fatalf("expected a connection error to be retried")
+	}
+
+	// Unlike the default policy, long Retry-After delays are respected.
+	retryAfter := http.Header{"Retry-After": []string{"120"}}
+	if delay, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusTooManyRequests, retryAfter, 1)); !retry || delay != 2*time.Minute {
+		t.Fatalf("expected to retry after the Retry-After delay, got %s, %t", delay, retry)
+	}
+	policy.MaxRetryAfter = time.Minute
+	if _, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusTooManyRequests, retryAfter, 1)); retry {
+		t.Fatalf("expected Retry-After delays above MaxRetryAfter not to be retried")
+	}
+}
+
+func TestFixedBackoff(t *testing.T) {
+	policy := option.FixedBackoff{Delay: 2 * time.Second}
+	if delay, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusBadGateway, nil, 5)); !retry || delay != 2*time.Second {
+		t.Fatalf("expected to retry after 2s, got %s, %t", delay, retry)
+	}
+	retryAfter := http.Header{"Retry-After-Ms": []string{"3500"}}
+	if delay, _ := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusTooManyRequests, retryAfter, 1)); delay != 3500*time.Millisecond {
+		t.Fatalf("expected to retry after the longer Retry-After delay, got %s", delay)
+	}
+	if _, retry := policy.RetryDelay(retryAttempt(http.MethodGet, http.StatusNotFound, nil, 1)); retry {
+		t.Fatalf("expected a 404 not to be retried")
+	}
+}
+
+func TestNeverRetryNonIdempotent(t *testing.T) {
+	policy := option.NeverRetryNonIdempotent(option.FixedBackoff{Delay: time.Second})
+	if _, retry := policy.RetryDelay(retryAttempt(http.MethodPost, http.StatusServiceUnavailable, nil, 1)); retry {
+		t.Fatalf("expected a POST without an idempotency key not to be retried")
+	}
+	a := retryAttempt(http.MethodPost, http.StatusServiceUnavailable, nil, 1)
+	a.Request.Header.Set("Idempotency-Key", "key")
+	if _, retry := policy.RetryDelay(a); !retry {
+		t.Fatalf("expected a POST with an idempotency key to be retried")
+	}
+	for _, method := range []string{http.MethodGet, http.MethodPut, http.MethodDelete} {
+		if _, retry := policy.RetryDelay(retryAttempt(method, http.StatusServiceUnavailable, nil, 1)); !retry {
+			t.Fatalf("expected a %s to be retried", method)
+		}
+	}
+}
+
+func TestWithRetryPolicy(t *testing.T) {
+	clock := newFakeClock()
+	var attempts []string
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			attempts = append(attempts, req.Header.Get("X-Stainless-Retry-Count"))
+			res := jsonResponse(http.StatusServiceUnavailable, `{}`)
+			switch len(attempts) {
+			case 2:
+				// The clock is at 12:00:00.5 after the first wait.
+				res.Header.Set("Retry-After", "Sat, 01 Jun 2024 12:01:30 GMT")
+			case 4:
+				res.StatusCode = http.StatusOK
+			}
+			return res, nil
+		},
+		option.WithMaxRetries(3),
+		option.WithClock(clock),
+		option.WithRetryPolicy(option.ExponentialBackoff{Rand: func() float64 { return 1 }}),
+	)
+
+	if _, err := client.Logs.Get(context.Background(), "req_123"); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if want := []string{"0", "1", "2", "3"}; !reflect.DeepEqual(attempts, want) {
+		t.Fatalf("expected attempts %v, got %v", want, attempts)
+	}
+	if want := []time.Duration{500 * time.Millisecond, 89500 * time.Millisecond, 2 * time.Second}; !reflect.DeepEqual(clock.Waits(), want) {
+		t.Fatalf("expected waits %v, got %v", want, clock.Waits())
+	}
+}
+
+func TestRetryWaitCanceled(t *testing.T) {
+	attempts := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return jsonResponse(http.StatusServiceUnavailable, `{}`), nil
+	}, option.WithRetryPolicy(option.FixedBackoff{Delay: time.Hour}))
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(50*time.Millisecond, cancel)
+
+	start := time.Now()
+	_, err := client.Logs.Get(ctx, "req_123")
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected the wait to be canceled, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected to stop waiting when canceled, took %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetrySkippedPastDeadline(t *testing.T) {
+	attempts := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		attempts++
+		return jsonResponse(http.StatusServiceUnavailable, `{}`), nil
+	}, option.WithRetryPolicy(option.FixedBackoff{Delay: time.Hour}))
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	start := time.Now()
+	_, err := client.Logs.Get(ctx, "req_123")
+	var apierr *ark.Error
+	if !errors.As(err, &apierr) || apierr.StatusCode != http.StatusServiceUnavailable {
+		t.Fatalf("expected the error of the last attempt, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("expected not to wait, took %s", elapsed)
+	}
+	if attempts != 1 {
+		t.Fatalf("expected 1 attempt, got %d", attempts)
+	}
+}
+
+func TestRetrySkippedPastDeadlineOfClock(t *testing.T) {
+	// The clock is ahead of the system clock, so the deadline of the context has
+	// already passed according to it.
+	clock := &fakeClock{now: time.Now().Add(2 * time.Minute)}
+	attempts := 0
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			attempts++
+			return jsonResponse(http.StatusServiceUnavailable, `{}`), nil
+		},
+		option.WithClock(clock),
+		option.WithRetryPolicy(option.FixedBackoff{Delay: time.Second}),
+	)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if _, err := client.Logs.Get(ctx, "req_123"); err == nil {
+		t.Fatalf("expected the error of the last attempt")
+	}
+	if attempts != 1 || len(clock.Waits()) != 0 {
+		t.Fatalf("expected 1 attempt without waiting, got %d attempts and waits %v", attempts, clock.Waits())
+	}
+}
@@ -0,0 +1,142 @@
+package ark
+
+import (
+	"context"
+
+	"github.com/ArkHQ-io/ark-go/option"
+	"github.com/ArkHQ-io/ark-go/packages/pagination"
+)
+
+// The interfaces below are satisfied by the services of a [Client], so that
+// code using the SDK can depend on them and be tested with fakes, like those of
+// package arktest. Each has the methods of the endpoints of its service; helpers
+// built on them, like [EmailService.SendBatchChunked] and the All iterators, are
+// only methods of the services.
+
+// EmailsAPI is the interface of [EmailService].
+type EmailsAPI interface {
+	Get(ctx context.Context, emailID string, query EmailGetParams, opts ...option.RequestOption) (*EmailGetResponse, error)
+	List(ctx context.Context, query EmailListParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[EmailListResponse], error)
+	ListAutoPaging(ctx context.Context, query EmailListParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[EmailListResponse]
+	GetDeliveries(ctx context.Context, emailID string, opts ...option.RequestOption) (*EmailGetDeliveriesResponse, error)
+	Retry(ctx context.Context, emailID string, opts ...option.RequestOption) (*EmailRetryResponse, error)
+	Send(ctx context.Context, params EmailSendParams, opts ...option.RequestOption) (*EmailSendResponse, error)
+	SendBatch(ctx context.Context, params EmailSendBatchParams, opts ...option.RequestOption) (*EmailSendBatchResponse, error)
+	SendRaw(ctx context.Context, body EmailSendRawParams, opts ...option.RequestOption) (*EmailSendRawResponse, error)
+}
+
+// LogsAPI is the interface of [LogService].
+type LogsAPI interface {
+	Get(ctx context.Context, requestID string, opts ...option.RequestOption) (*LogGetResponse, error)
+	List(ctx context.Context, query LogListParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[LogEntry], error)
+	ListAutoPaging(ctx context.Context, query LogListParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[LogEntry]
+}
+
+// UsageAPI is the interface of [UsageService].
+type UsageAPI interface {
+	Get(ctx context.Context, query UsageGetParams, opts ...option.RequestOption) (*OrgUsageSummary, error)
+	Export(ctx context.Context, query UsageExportParams, opts ...option.RequestOption) (*[]UsageExportResponse, error)
+	ListTenants(ctx context.Context, query UsageListTenantsParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[TenantUsageItem], error)
+	ListTenantsAutoPaging(ctx context.Context, query UsageListTenantsParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[TenantUsageItem]
+}
+
+// LimitsAPI is the interface of [LimitService].
+type LimitsAPI interface {
+	Get(ctx context.Context, opts ...option.RequestOption) (*LimitGetResponse, error)
+}
+
+// TenantsAPI is the interface of [TenantService].
+type TenantsAPI interface {
+	New(ctx context.Context, body TenantNewParams, opts ...option.RequestOption) (*TenantNewResponse, error)
+	Get(ctx context.Context, tenantID string, opts ...option.RequestOption) (*TenantGetResponse, error)
+	Update(ctx context.Context, tenantID string, body TenantUpdateParams, opts ...option.RequestOption) (*TenantUpdateResponse, error)
+	List(ctx context.Context, query TenantListParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[Tenant], error)
+	ListAutoPaging(ctx context.Context, query TenantListParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[Tenant]
+	Delete(ctx context.Context, tenantID string, opts ...option.RequestOption) (*TenantDeleteResponse, error)
+}
+
+// TenantCredentialsAPI is the interface of [TenantCredentialService].
+type TenantCredentialsAPI interface {
+	New(ctx context.Context, tenantID string, body TenantCredentialNewParams, opts ...option.RequestOption) (*TenantCredentialNewResponse, error)
+	Get(ctx context.Context, credentialID int64, params TenantCredentialGetParams, opts ...option.RequestOption) (*TenantCredentialGetResponse, error)
+	Update(ctx context.Context, credentialID int64, params TenantCredentialUpdateParams, opts ...option.RequestOption) (*TenantCredentialUpdateResponse, error)
+	List(ctx context.Context, tenantID string, query TenantCredentialListParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[TenantCredentialListResponse], error)
+	ListAutoPaging(ctx context.Context, tenantID string, query TenantCredentialListParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[TenantCredentialListResponse]
+	Delete(ctx context.Context, credentialID int64, body TenantCredentialDeleteParams, opts ...option.RequestOption) (*TenantCredentialDeleteResponse, error)
+}
+
+// TenantDomainsAPI is the interface of [TenantDomainService].
+type TenantDomainsAPI interface {
+	New(ctx context.Context, tenantID string, body TenantDomainNewParams, opts ...option.RequestOption) (*TenantDomainNewResponse, error)
+	Get(ctx context.Context, domainID string, query TenantDomainGetParams, opts ...option.RequestOption) (*TenantDomainGetResponse, error)
+	List(ctx context.Context, tenantID string, opts ...option.RequestOption) (*TenantDomainListResponse, error)
+	Delete(ctx context.Context, domainID string, body TenantDomainDeleteParams, opts ...option.RequestOption) (*TenantDomainDeleteResponse, error)
+	Verify(ctx context.Context, domainID string, body TenantDomainVerifyParams, opts ...option.RequestOption) (*TenantDomainVerifyResponse, error)
+}
+
+// TenantSuppressionsAPI is the interface of [TenantSuppressionService].
+type TenantSuppressionsAPI interface {
+	New(ctx context.Context, tenantID string, body TenantSuppressionNewParams, opts ...option.RequestOption) (*TenantSuppressionNewResponse, error)
+	Get(ctx context.Context, email string, query TenantSuppressionGetParams, opts ...option.RequestOption) (*TenantSuppressionGetResponse, error)
+	List(ctx context.Context, tenantID string, query TenantSuppressionListParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[TenantSuppressionListResponse], error)
+	ListAutoPaging(ctx context.Context, tenantID string, query TenantSuppressionListParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[TenantSuppressionListResponse]
+	Delete(ctx context.Context, email string, body TenantSuppressionDeleteParams, opts ...option.RequestOption) (*TenantSuppressionDeleteResponse, error)
+}
+
+// TenantTrackingAPI is the interface of [TenantTrackingService].
+type TenantTrackingAPI interface {
+	New(ctx context.Context, tenantID string, body TenantTrackingNewParams, opts ...option.RequestOption) (*TenantTrackingNewResponse, error)
+	Get(ctx context.Context, trackingID string, query TenantTrackingGetParams, opts ...option.RequestOption) (*TenantTrackingGetResponse, error)
+	Update(ctx context.Context, trackingID string, params TenantTrackingUpdateParams, opts ...option.RequestOption) (*TenantTrackingUpdateResponse, error)
+	List(ctx context.Context, tenantID string, opts ...option.RequestOption) (*TenantTrackingListResponse, error)
+	Delete(ctx context.Context, trackingID string, body TenantTrackingDeleteParams, opts ...option.RequestOption) (*TenantTrackingDeleteResponse, error)
+	Verify(ctx context.Context, trackingID string, body TenantTrackingVerifyParams, opts ...option.RequestOption) (*TenantTrackingVerifyResponse, error)
+}
+
+// TenantUsageAPI is the interface of [TenantUsageService].
+type TenantUsageAPI interface {
+	Get(ctx context.Context, tenantID string, query TenantUsageGetParams, opts ...option.RequestOption) (*TenantUsageGetResponse, error)
+	GetTimeseries(ctx context.Context, tenantID string, query TenantUsageGetTimeseriesParams, opts ...option.RequestOption) (*TenantUsageGetTimeseriesResponse, error)
+}
+
+// TenantWebhooksAPI is the interface of [TenantWebhookService].
+type TenantWebhooksAPI interface {
+	New(ctx context.Context, tenantID string, body TenantWebhookNewParams, opts ...option.RequestOption) (*TenantWebhookNewResponse, error)
+	Get(ctx context.Context, webhookID string, query TenantWebhookGetParams, opts ...option.RequestOption) (*TenantWebhookGetResponse, error)
+	Update(ctx context.Context, webhookID string, params TenantWebhookUpdateParams, opts ...option.RequestOption) (*TenantWebhookUpdateResponse, error)
+	List(ctx context.Context, tenantID string, opts ...option.RequestOption) (*TenantWebhookListResponse, error)
+	Delete(ctx context.Context, webhookID string, body TenantWebhookDeleteParams, opts ...option.RequestOption) (*TenantWebhookDeleteResponse, error)
+	ListDeliveries(ctx context.Context, webhookID string, params TenantWebhookListDeliveriesParams, opts ...option.RequestOption) (*TenantWebhookListDeliveriesResponse, error)
+	ReplayDelivery(ctx context.Context, deliveryID string, body TenantWebhookReplayDeliveryParams, opts ...option.RequestOption) (*TenantWebhookReplayDeliveryResponse, error)
+	GetDelivery(ctx context.Context, deliveryID string, query TenantWebhookGetDeliveryParams, opts ...option.RequestOption) (*TenantWebhookGetDeliveryResponse, error)
+	Test(ctx context.Context, webhookID string, params TenantWebhookTestParams, opts ...option.RequestOption) (*TenantWebhookTestResponse, error)
+}
+
+// PlatformWebhooksAPI is the interface of [PlatformWebhookService].
+type PlatformWebhooksAPI interface {
+	New(ctx context.Context, body PlatformWebhookNewParams, opts ...option.RequestOption) (*PlatformWebhookNewResponse, error)
+	Get(ctx context.Context, webhookID string, opts ...option.RequestOption) (*PlatformWebhookGetResponse, error)
+	Update(ctx context.Context, webhookID string, body PlatformWebhookUpdateParams, opts ...option.RequestOption) (*PlatformWebhookUpdateResponse, error)
+	List(ctx context.Context, opts ...option.RequestOption) (*PlatformWebhookListResponse, error)
+	Delete(ctx context.Context, webhookID string, opts ...option.RequestOption) (*PlatformWebhookDeleteResponse, error)
+	ListDeliveries(ctx context.Context, query PlatformWebhookListDeliveriesParams, opts ...option.RequestOption) (*pagination.PageNumberPagination[PlatformWebhookListDeliveriesResponse], error)
+	ListDeliveriesAutoPaging(ctx context.Context, query PlatformWebhookListDeliveriesParams, opts ...option.RequestOption) *pagination.PageNumberPaginationAutoPager[PlatformWebhookListDeliveriesResponse]
+	ReplayDelivery(ctx context.Context, deliveryID string, opts ...option.RequestOption) (*PlatformWebhookReplayDeliveryResponse, error)
+	GetDelivery(ctx context.Context, deliveryID string, opts ...option.RequestOption) (*PlatformWebhookGetDeliveryResponse, error)
+	Test(ctx context.Context, webhookID string, body PlatformWebhookTestParams, opts ...option.RequestOption) (*PlatformWebhookTestResponse, error)
+}
+
+var (
+	_ EmailsAPI             = (*EmailService)(nil)
+	_ LogsAPI               = (*LogService)(nil)
+	_ UsageAPI              = (*UsageService)(nil)
+	_ LimitsAPI             = (*LimitService)(nil)
+	_ TenantsAPI            = (*TenantService)(nil)
+	_ TenantCredentialsAPI  = (*TenantCredentialService)(nil)
+	_ TenantDomainsAPI      = (*TenantDomainService)(nil)
+	_ TenantSuppressionsAPI = (*TenantSuppressionService)(nil)
+	_ TenantTrackingAPI     = (*TenantTrackingService)(nil)
+	_ TenantUsageAPI        = (*TenantUsageService)(nil)
+	_ TenantWebhooksAPI     = (*TenantWebhookService)(nil)
+	_ PlatformWebhooksAPI   = (*PlatformWebhookService)(nil)
+)
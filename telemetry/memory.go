@@ -0,0 +1,152 @@
+package telemetry
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// InMemory is a [Tracer] and [Meter] which keeps everything it records in
+// memory, to test instrumentation. It is safe for concurrent use.
+type InMemory struct {
+	mu           sync.Mutex
+	spans        []*SpanData
+	measurements []Measurement
+}
+
+// SpanData is a span recorded by [InMemory].
+type SpanData struct {
+	Name string
+	// ID is the index of the span in [InMemory.Spans], plus 1.
+	ID int
+	// ParentID is the ID of the parent span, or 0 for root spans.
+	ParentID   int
+	Attributes map[string]any
+	Errors     []error
+	Start, End time.Time
+	Ended      bool
+}
+
+// Measurement is a value added to a counter or recorded in a histogram of
+// [InMemory].
+type Measurement struct {
+	Name       string
+	Value      float64
+	Attributes map[string]any
+}
+
+// NewInMemory returns an empty InMemory.
+func NewInMemory() *InMemory {
+	return &InMemory{}
+}
+
+type spanKey struct{}
+
+type memorySpan struct {
+	m    *InMemory
+	data *SpanData
+}
+
+func (m *InMemory) Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	data := &SpanData{
+		Name:       name,
+		ID:         len(m.spans) + 1,
+		Attributes: map[string]any{},
+		Start:      time.Now(),
+	}
+	if parent, ok := ctx.Value(spanKey{}).(*memorySpan); ok {
+		data.ParentID = parent.data.ID
+	}
+	setAttributes(data.Attributes, attrs)
+	m.spans = append(m.spans, data)
+	span := &memorySpan{m: m, data: data}
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (s *memorySpan) SetAttributes(attrs ...Attribute) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	setAttributes(s.data.Attributes, attrs)
+}
+
+func (s *memorySpan) RecordError(err error) {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.data.Errors = append(s.data.Errors, err)
+}
+
+func (s *memorySpan) End() {
+	s.m.mu.Lock()
+	defer s.m.mu.Unlock()
+	s.data.End = time.Now()
+	s.data.Ended = true
+}
+
+func (m *InMemory) Add(ctx context.Context, name string, value int64, attrs ...Attribute) {
+	m.record(name, float64(value), attrs)
+}
+
+func (m *InMemory) Record(ctx context.Context, name string, value float64, attrs ...Attribute) {
+	m.record(name, value, attrs)
+}
+
+func (m *InMemory) record(name string, value float64, attrs []Attribute) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	measurement := Measurement{Name: name, Value: value, Attributes: map[string]any{}}
+	setAttributes(measurement.Attributes, attrs)
+	m.measurements = append(m.measurements, measurement)
+}
+
+// Spans returns copies of the spans started so far, in the order they were
+// started.
+func (m *InMemory) Spans() []SpanData {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	spans := make([]SpanData, len(m.spans))
+	for i, span := range m.spans {
+		spans[i] = *span
+		spans[i].Attributes = copyAttributes(span.Attributes)
+		spans[i].Errors = append([]error(nil), span.Errors...)
+	}
+	return spans
+}
+
+// Measurements returns the measurements of the metric name, in the order they
+// were recorded.
+func (m *InMemory) Measurements(name string) []Measurement {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var measurements []Measurement
+	for _, measurement := range m.measurements {
+		if measurement.Name == name {
+			measurement.Attributes = copyAttributes(measurement.Attributes)
+			measurements = append(measurements, measurement)
+		}
+	}
+	return measurements
+}
+
+// Reset forgets every span and measurement recorded so far.
+func (m *InMemory) Reset() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.spans = nil
+	m.measurements = nil
+}
+
+func setAttributes(dst map[string]any, attrs []Attribute) {
+	for _, attr := range attrs {
+		dst[attr.Key] = attr.Value
+	}
+}
+
+func copyAttributes(attrs map[string]any) map[string]any {
+	c := make(map[string]any, len(attrs))
+	for k, v := range attrs {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,79 @@
+// Package telemetry defines the tracing and metrics interfaces used by
+// [option.WithTracer] and [option.WithMeter]. They follow the shape of the
+// OpenTelemetry API, so that adapting an OpenTelemetry tracer or meter only
+// takes a few lines, without the SDK depending on OpenTelemetry.
+//
+// [InMemory] records spans and measurements, to test instrumentation.
+//
+// [option.WithTracer]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go/option#WithTracer
+// [option.WithMeter]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go/option#WithMeter
+package telemetry
+
+import "context"
+
+// Names of the attributes set on spans and measurements.
+const (
+	// AttrOperation is the method and path template of a request, e.g.
+	// "GET /v1/emails/{id}".
+	AttrOperation = "ark.operation"
+	// AttrTenantID is the ID of the tenant of a request, for tenant endpoints.
+	AttrTenantID = "ark.tenant_id"
+	// AttrRetryCount is the number of retries of a request, or the number of the
+	// retry of an attempt.
+	AttrRetryCount = "ark.retry_count"
+	// AttrRequestID is the ID of the request returned by the API, which can be
+	// looked up with the logs endpoints.
+	AttrRequestID  = "ark.request_id"
+	AttrMethod     = "http.request.method"
+	AttrStatusCode = "http.response.status_code"
+	AttrHost       = "server.address"
+)
+
+// Names of the metrics.
+const (
+	// MetricRequests counts requests, after their last attempt.
+	MetricRequests = "ark.client.requests"
+	// MetricRetries counts retries.
+	MetricRetries = "ark.client.retries"
+	// MetricRequestDuration is the duration of requests in seconds, including
+	// all of their attempts.
+	MetricRequestDuration = "ark.client.request.duration"
+	// MetricAttemptDuration is the duration of single attempts in seconds.
+	MetricAttemptDuration = "ark.client.attempt.duration"
+)
+
+// Attribute is a key-value pair describing a span or a measurement.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+// String returns a string attribute.
+func String(key, value string) Attribute { return Attribute{Key: key, Value: value} }
+
+// Int returns an integer attribute.
+func Int(key string, value int) Attribute { return Attribute{Key: key, Value: value} }
+
+// Tracer starts spans, like the OpenTelemetry trace.Tracer.
+type Tracer interface {
+	// Start starts a span which is a child of the span of ctx, if any, and
+	// returns a context containing it.
+	Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, Span)
+}
+
+// Span is an operation being traced, like the OpenTelemetry trace.Span.
+type Span interface {
+	SetAttributes(attrs ...Attribute)
+	// RecordError records that the operation failed with err.
+	RecordError(err error)
+	End()
+}
+
+// Meter records measurements, like the instruments of an OpenTelemetry
+// metric.Meter.
+type Meter interface {
+	// Add adds value to the counter name.
+	Add(ctx context.Context, name string, value int64, attrs ...Attribute)
+	// Record records value in the histogram name.
+	Record(ctx context.Context, name string, value float64, attrs ...Attribute)
+}
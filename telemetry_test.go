@@ -0,0 +1,142 @@
+package ark_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"reflect"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+	"github.com/ArkHQ-io/ark-go/telemetry"
+)
+
+func TestTelemetry(t *testing.T) {
+	mem := telemetry.NewInMemory()
+	attempts := 0
+	client := newTestClient(
+		func(req *http.Request) (*http.Response, error) {
+			attempts++
+			status := http.StatusOK
+			if attempts == 1 {
+				status = http.StatusServiceUnavailable
+			}
+			return jsonResponse(status, `{"success":true,"data":{"id":"tn_123"},"meta":{"requestId":"req_`+string(rune('0'+attempts))+`"}}`), nil
+		},
+		option.WithTracer(mem),
+		option.WithMeter(mem),
+		option.WithClock(newFakeClock()),
+	)
+
+	res, err := client.Tenants.Get(context.Background(), "tn_123")
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+	if res.Data.ID != "tn_123" {
+		t.Fatalf("expected the response to be read after its request ID, got %+v", res.Data)
+	}
+
+	spans := mem.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("expected a span for the request and each attempt, got %+v", spans)
+	}
+	request := map[string]any{
+		telemetry.AttrOperation: "GET /v1/tenants/{id}",
+		telemetry.AttrMethod:    "GET",
+		telemetry.AttrHost:      "api.arkhq.io",
+		telemetry.AttrTenantID:  "tn_123",
+	}
+	want := []telemetry.SpanData{
+		{Name: "GET /v1/tenants/{id}", ID: 1, Attributes: mergeAttrs(request, map[string]any{
+			telemetry.AttrRetryCount: 1,
+			telemetry.AttrStatusCode: 200,
+			telemetry.AttrRequestID:  "req_2",
+		})},
+		{Name: "ark.attempt", ID: 2, ParentID: 1, Attributes: mergeAttrs(request, map[string]any{
+			telemetry.AttrRetryCount: 0,
+			telemetry.AttrStatusCode: 503,
+			telemetry.AttrRequestID:  "req_1",
+		})},
+		{Name: "ark.attempt", ID: 3, ParentID: 1, Attributes: mergeAttrs(request, map[string]any{
+			telemetry.AttrRetryCount: 1,
+			telemetry.AttrStatusCode: 200,
+			telemetry.AttrRequestID:  "req_2",
+		})},
+	}
+	for i, span := range spans {
+		if !span.Ended || span.End.Before(span.Start) {
+			t.Fatalf("expected span %d to be ended", i)
+		}
+		span.Start, span.End, span.Ended = want[i].Start, want[i].End, false
+		if !reflect.DeepEqual(span, want[i]) {
+			t.Fatalf("expected span %d to be %+v, got %+v", i, want[i], span)
+		}
+	}
+
+	if m := mem.Measurements(telemetry.MetricRequests); len(m) != 1 || m[0].Value != 1 || m[0].Attributes[telemetry.AttrStatusCode] != 200 {
+		t.Fatalf("expected a request to be counted, got %+v", m)
+	}
+	if m := mem.Measurements(telemetry.MetricRetries); len(m) != 1 || m[0].Value != 1 {
+		t.Fatalf("expected a retry to be counted, got %+v", m)
+	}
+	if m := mem.Measurements(telemetry.MetricRequestDuration); len(m) != 1 || m[0].Value < 0 {
+		t.Fatalf("expected the duration of the request, got %+v", m)
+	}
+	m := mem.Measurements(telemetry.MetricAttemptDuration)
+	if len(m) != 2 || m[0].Attributes[telemetry.AttrStatusCode] != 503 || m[1].Attributes[telemetry.AttrRetryCount] != 1 {
+		t.Fatalf("expected the duration of each attempt, got %+v", m)
+	}
+}
+
+func TestTelemetryError(t *testing.T) {
+	mem := telemetry.NewInMemory()
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		return nil, errors.New("connection refused")
+	}, option.WithMaxRetries(0), option.WithTracer(mem))
+
+	if _, err := client.Logs.Get(context.Background(), "req_123"); err == nil {
+		t.Fatalf("expected an error")
+	}
+	spans := mem.Spans()
+	if len(spans) != 2 || len(spans[0].Errors) != 1 || len(spans[1].Errors) != 1 {
+		t.Fatalf("expected the error to be recorded on both spans, got %+v", spans)
+	}
+	if _, ok := spans[0].Attributes[telemetry.AttrStatusCode]; ok {
+		t.Fatalf("expected no status code without a response, got %+v", spans[0].Attributes)
+	}
+}
+
+func mergeAttrs(attrs ...map[string]any) map[string]any {
+	merged := map[string]any{}
+	for _, a := range attrs {
+		for k, v := range a {
+			merged[k] = v
+		}
+	}
+	return merged
+}
+
+func TestTelemetryPages(t *testing.T) {
+	mem := telemetry.NewInMemory()
+	api := &pagedAPI{totalPages: 3, perPage: 2}
+	client := newTestClient(api.serve, option.WithMaxRetries(0))
+	iter := client.Emails.ListAutoPaging(context.Background(), ark.EmailListParams{}, option.WithTracer(mem))
+	for iter.Next() {
+	}
+	if err := iter.Err(); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+
+	var names []string
+	for _, span := range mem.Spans() {
+		if !span.Ended {
+			t.Fatalf("expected span %s to be ended", span.Name)
+		}
+		names = append(names, span.Name)
+	}
+	want := []string{"GET /v1/emails", "ark.attempt", "GET /v1/emails", "ark.attempt", "GET /v1/emails", "ark.attempt"}
+	if !reflect.DeepEqual(names, want) {
+		t.Fatalf("expected a request span per page, got %v", names)
+	}
+}
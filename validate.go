@@ -0,0 +1,188 @@
+package ark
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits enforced by the API on sent emails, see [EmailSendParams].
+const (
+	maxRecipients     = 50
+	maxBatchEmails    = 100
+	maxMetadataKeys   = 10
+	maxMetadataKeyLen = 40
+	maxMetadataValLen = 500
+	maxMetadataSize   = 4 << 10
+	maxBodyLen        = 5 << 20
+	maxMessageSize    = 14 << 20
+)
+
+var metadataKeyPattern = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_]*$`)
+
+// Validate checks the params against the limits documented on their fields, so
+// that invalid emails can be rejected without making a request. It returns a
+// [*ValidationError] listing every offending field, or nil.
+//
+// Validate only checks what can be known locally; the API may still reject the
+// email, e.g. when the domain of From is not verified. See
+// [option.WithValidation] to validate params before every send.
+//
+// [option.WithValidation]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go/option#WithValidation
+func (r EmailSendParams) Validate() error {
+	var v validator
+	v.required("from", r.From)
+	v.required("subject", r.Subject)
+	v.recipients("to", r.To)
+	v.body(r.HTML.Value, r.Text.Value)
+	v.metadata("metadata", r.Metadata)
+
+	for i, a := range r.Attachments {
+		path := fmt.Sprintf("attachments[%d]", i)
+		v.required(path+".content", a.Content)
+		v.required(path+".contentType", a.ContentType)
+		v.required(path+".filename", a.Filename)
+	}
+	if r.size() > maxMessageSize {
+		v.add("attachments", "the message including attachments must be at most 14MB")
+	}
+	return v.err()
+}
+
+// Validate checks the params and each of its emails, see
+// [EmailSendParams.Validate].
+func (r EmailSendBatchParams) Validate() error {
+	var v validator
+	v.required("from", r.From)
+	switch {
+	case len(r.Emails) == 0:
+		v.add("emails", "is required")
+	case len(r.Emails) > maxBatchEmails:
+		v.add("emails", fmt.Sprintf("must contain at most %d emails", maxBatchEmails))
+	}
+	for i, email := range r.Emails {
+		v.merge(fmt.Sprintf("emails[%d]", i), email.Validate())
+	}
+	return v.err()
+}
+
+// Validate checks a single email of a batch, see [EmailSendParams.Validate].
+func (r EmailSendBatchParamsEmail) Validate() error {
+	var v validator
+	v.required("subject", r.Subject)
+	v.recipients("to", r.To)
+	v.body(r.HTML.Value, r.Text.Value)
+	v.metadata("metadata", r.Metadata)
+	return v.err()
+}
+
+// Validate checks that the params have a sender, recipients and a base64-encoded
+// message of at most 14MB, see [EmailSendParams.Validate].
+func (r EmailSendRawParams) Validate() error {
+	var v validator
+	v.required("from", r.From)
+	v.recipients("to", r.To)
+	if r.RawMessage == "" {
+		v.add("rawMessage", "is required")
+	} else if base64.StdEncoding.DecodedLen(len(r.RawMessage)) > maxMessageSize {
+		v.add("rawMessage", "the message must be at most 14MB")
+	} else if _, err := base64.StdEncoding.DecodeString(r.RawMessage); err != nil {
+		v.add("rawMessage", "must be base64-encoded")
+	}
+	return v.err()
+}
+
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) add(path, reason string) {
+	v.fields = append(v.fields, FieldError{Path: path, Reason: reason})
+}
+
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+// merge adds the fields of a nested validation error under prefix.
+func (v *validator) merge(prefix string, err error) {
+	if verr, ok := err.(*ValidationError); ok {
+		for _, f := range verr.Fields {
+			v.add(prefix+"."+f.Path, f.Reason)
+		}
+	}
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.add(path, "is required")
+	}
+}
+
+func (v *validator) recipients(path string, to []string) {
+	if len(to) == 0 {
+		v.add(path, "is required")
+	} else if len(to) > maxRecipients {
+		v.add(path, fmt.Sprintf("must contain at most %d recipients", maxRecipients))
+	}
+	for i, addr := range to {
+		if strings.TrimSpace(addr) == "" {
+			v.add(fmt.Sprintf("%s[%d]", path, i), "must not be empty")
+		}
+	}
+}
+
+// body checks that an email has an HTML or text body, of at most 5MB each.
+func (v *validator) body(html, text string) {
+	if html == "" && text == "" {
+		v.add("html", "is required when text is not set")
+	}
+	for _, f := range []struct{ path, value string }{{"html", html}, {"text", text}} {
+		if len(f.value) > maxBodyLen && utf8.RuneCountInString(f.value) > maxBodyLen {
+			v.add(f.path, "must be at most 5MB")
+		}
+	}
+}
+
+func (v *validator) metadata(path string, metadata map[string]string) {
+	if len(metadata) > maxMetadataKeys {
+		v.add(path, fmt.Sprintf("must contain at most %d keys", maxMetadataKeys))
+	}
+
+	keys := make([]string, 0, len(metadata))
+	for key := range metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		value := metadata[key]
+		switch {
+		case len(key) > maxMetadataKeyLen:
+			v.add(path+"."+key, fmt.Sprintf("key must be at most %d characters", maxMetadataKeyLen))
+		case !metadataKeyPattern.MatchString(key):
+			v.add(path+"."+key, "key must start with a letter and contain only letters, digits and underscores")
+		}
+		switch n := utf8.RuneCountInString(value); {
+		case n == 0:
+			v.add(path+"."+key, "value must not be empty")
+		case n > maxMetadataValLen:
+			v.add(path+"."+key, fmt.Sprintf("value must be at most %d characters", maxMetadataValLen))
+		case strings.IndexFunc(value, unicode.IsControl) >= 0:
+			v.add(path+"."+key, "value must not contain control characters")
+		}
+	}
+
+	if len(metadata) > 0 {
+		if data, err := json.Marshal(metadata); err == nil && len(data) > maxMetadataSize {
+			v.add(path, "must be at most 4KB when JSON-encoded")
+		}
+	}
+}
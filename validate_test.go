@@ -0,0 +1,212 @@
+package ark_test
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go"
+	"github.com/ArkHQ-io/ark-go/option"
+	"github.com/ArkHQ-io/ark-go/packages/param"
+)
+
+func validationFields(t *testing.T, err error) []ark.FieldError {
+	t.Helper()
+	if err == nil {
+		return nil
+	}
+	var verr *ark.ValidationError
+	if !errors.As(err, &verr) {
+		t.Fatalf("expected a ValidationError, got %v", err)
+	}
+	return verr.Fields
+}
+
+func TestEmailSendParamsValidate(t *testing.T) {
+	valid := func() ark.EmailSendParams {
+		return ark.EmailSendParams{
+			From:     "hello@yourdomain.com",
+			Subject:  "Hello World",
+			To:       []string{"user@example.com"},
+			HTML:     ark.String("<h1>Welcome!</h1>"),
+			Metadata: map[string]string{"user_id": "usr_123456"},
+		}
+	}
+	many := func(n int, f func(i int) string) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = f(i)
+		}
+		return out
+	}
+
+	tests := map[string]struct {
+		modify func(p *ark.EmailSendParams)
+		want   []ark.FieldError
+	}{
+		"valid": {
+			modify: func(p *ark.EmailSendParams) {},
+		},
+		"missing fields": {
+			modify: func(p *ark.EmailSendParams) {
+				p.From, p.Subject, p.To = "", "", nil
+			},
+			want: []ark.FieldError{
+				{Path: "from", Reason: "is required"},
+				{Path: "subject", Reason: "is required"},
+				{Path: "to", Reason: "is required"},
+			},
+		},
+		"too many recipients": {
+			modify: func(p *ark.EmailSendParams) {
+				p.To = many(51, func(i int) string { return fmt.Sprintf("user%d@example.com", i) })
+				p.To[3] = ""
+			},
+			want: []ark.FieldError{
+				{Path: "to", Reason: "must contain at most 50 recipients"},
+				{Path: "to[3]", Reason: "must not be empty"},
+			},
+		},
+		"metadata": {
+			modify: func(p *ark.EmailSendParams) {
+				p.Metadata = map[string]string{
+					"order-id":              "123",
+					"empty":                 "",
+					"multiline":             "a\nb",
+					strings.Repeat("k", 41): "v",
+					"long":                  strings.Repeat("v", 501),
+				}
+			},
+			want: []ark.FieldError{
+				{Path: "metadata.empty", Reason: "value must not be empty"},
+				{Path: "metadata." + strings.Repeat("k", 41), Reason: "key must be at most 40 characters"},
+				{Path: "metadata.long", Reason: "value must be at most 500 characters"},
+				{Path: "metadata.multiline", Reason: "value must not contain control characters"},
+				{Path: "metadata.order-id", Reason: "key must start with a letter and contain only letters, digits and underscores"},
+			},
+		},
+		"metadata size": {
+			modify: func(p *ark.EmailSendParams) {
+				p.Metadata = map[string]string{}
+				for i := 0; i < 10; i++ {
+					p.Metadata[fmt.Sprintf("key%d", i)] = strings.Repeat("v", 450)
+				}
+			},
+			want: []ark.FieldError{
+				{Path: "metadata", Reason: "must be at most 4KB when JSON-encoded"},
+			},
+		},
+		"missing body": {
+			modify: func(p *ark.EmailSendParams) {
+				p.HTML = param.Opt[string]{}
+			},
+			want: []ark.FieldError{
+				{Path: "html", Reason: "is required when text is not set"},
+			},
+		},
+		"text body": {
+			modify: func(p *ark.EmailSendParams) {
+				p.HTML, p.Text = param.Opt[string]{}, ark.String("Welcome!")
+			},
+		},
+		"too many metadata keys": {
+			modify: func(p *ark.EmailSendParams) {
+				p.Metadata = map[string]string{}
+				for i := 0; i < 11; i++ {
+					p.Metadata[fmt.Sprintf("key%d", i)] = "v"
+				}
+			},
+			want: []ark.FieldError{
+				{Path: "metadata", Reason: "must contain at most 10 keys"},
+			},
+		},
+		"body size": {
+			modify: func(p *ark.EmailSendParams) {
+				p.HTML = ark.String(strings.Repeat("a", 5<<20+1))
+			},
+			want: []ark.FieldError{
+				{Path: "html", Reason: "must be at most 5MB"},
+			},
+		},
+		"attachments": {
+			modify: func(p *ark.EmailSendParams) {
+				p.Attachments = []ark.EmailSendParamsAttachment{
+					{Content: base64.StdEncoding.EncodeToString(make([]byte, 14<<20)), ContentType: "application/pdf", Filename: "big.pdf"},
+					{Content: "aGk=", ContentType: "text/plain"},
+				}
+			},
+			want: []ark.FieldError{
+				{Path: "attachments[1].filename", Reason: "is required"},
+				{Path: "attachments", Reason: "the message including attachments must be at most 14MB"},
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			params := valid()
+			test.modify(&params)
+			if got := validationFields(t, params.Validate()); !reflect.DeepEqual(got, test.want) {
+				t.Fatalf("expected %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestEmailSendBatchParamsValidate(t *testing.T) {
+	params := ark.EmailSendBatchParams{
+		From: "hello@yourdomain.com",
+		Emails: []ark.EmailSendBatchParamsEmail{
+			{Subject: "Hello", To: []string{"user@example.com"}, Text: ark.String("Hi!")},
+			{Subject: "Hello", Metadata: map[string]string{"order-id": "123"}},
+		},
+	}
+	want := []ark.FieldError{
+		{Path: "emails[1].to", Reason: "is required"},
+		{Path: "emails[1].html", Reason: "is required when text is not set"},
+		{Path: "emails[1].metadata.order-id", Reason: "key must start with a letter and contain only letters, digits and underscores"},
+	}
+	if got := validationFields(t, params.Validate()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+}
+
+func TestEmailSendRawParamsValidate(t *testing.T) {
+	params := ark.EmailSendRawParams{
+		From:       "hello@yourdomain.com",
+		To:         []string{"user@example.com"},
+		RawMessage: "From: hello@yourdomain.com\r\n\r\nHi",
+	}
+	want := []ark.FieldError{{Path: "rawMessage", Reason: "must be base64-encoded"}}
+	if got := validationFields(t, params.Validate()); !reflect.DeepEqual(got, want) {
+		t.Fatalf("expected %+v, got %+v", want, got)
+	}
+
+	params.RawMessage = base64.StdEncoding.EncodeToString([]byte(params.RawMessage))
+	if err := params.Validate(); err != nil {
+		t.Fatalf("err should be nil: %s", err.Error())
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	requests := 0
+	client := newTestClient(func(req *http.Request) (*http.Response, error) {
+		requests++
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	}, option.WithValidation())
+	_, err := client.Emails.Send(context.Background(), ark.EmailSendParams{
+		From:    "hello@yourdomain.com",
+		Subject: "Hello World",
+	})
+	if !errors.Is(err, ark.ErrValidation) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if requests != 0 {
+		t.Fatalf("expected no requests to be made, got %d", requests)
+	}
+}
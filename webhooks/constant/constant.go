@@ -0,0 +1,47 @@
+// Package constant defines the constant types of the event discriminators of
+// webhook deliveries, like those of [github.com/ArkHQ-io/ark-go/shared/constant].
+package constant
+
+import (
+	shimjson "github.com/ArkHQ-io/ark-go/internal/encoding/json"
+)
+
+type DomainDNSError string        // Always "DomainDNSError"
+type MessageBounced string        // Always "MessageBounced"
+type MessageDelayed string        // Always "MessageDelayed"
+type MessageDeliveryFailed string // Always "MessageDeliveryFailed"
+type MessageHeld string           // Always "MessageHeld"
+type MessageLinkClicked string    // Always "MessageLinkClicked"
+type MessageLoaded string         // Always "MessageLoaded"
+type MessageSent string           // Always "MessageSent"
+
+func (c DomainDNSError) Default() DomainDNSError               { return "DomainDNSError" }
+func (c MessageBounced) Default() MessageBounced               { return "MessageBounced" }
+func (c MessageDelayed) Default() MessageDelayed               { return "MessageDelayed" }
+func (c MessageDeliveryFailed) Default() MessageDeliveryFailed { return "MessageDeliveryFailed" }
+func (c MessageHeld) Default() MessageHeld                     { return "MessageHeld" }
+func (c MessageLinkClicked) Default() MessageLinkClicked       { return "MessageLinkClicked" }
+func (c MessageLoaded) Default() MessageLoaded                 { return "MessageLoaded" }
+func (c MessageSent) Default() MessageSent                     { return "MessageSent" }
+
+func (c DomainDNSError) MarshalJSON() ([]byte, error)        { return marshalString(c) }
+func (c MessageBounced) MarshalJSON() ([]byte, error)        { return marshalString(c) }
+func (c MessageDelayed) MarshalJSON() ([]byte, error)        { return marshalString(c) }
+func (c MessageDeliveryFailed) MarshalJSON() ([]byte, error) { return marshalString(c) }
+func (c MessageHeld) MarshalJSON() ([]byte, error)           { return marshalString(c) }
+func (c MessageLinkClicked) MarshalJSON() ([]byte, error)    { return marshalString(c) }
+func (c MessageLoaded) MarshalJSON() ([]byte, error)         { return marshalString(c) }
+func (c MessageSent) MarshalJSON() ([]byte, error)           { return marshalString(c) }
+
+type constant[T any] interface {
+	Default() T
+	*T
+}
+
+func marshalString[T ~string, PT constant[T]](v T) ([]byte, error) {
+	var zero T
+	if v == zero {
+		v = PT(&v).Default()
+	}
+	return shimjson.Marshal(string(v))
+}
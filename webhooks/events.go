@@ -0,0 +1,563 @@
+package webhooks
+
+import (
+	"encoding/json"
+
+	"github.com/ArkHQ-io/ark-go/internal/apijson"
+	"github.com/ArkHQ-io/ark-go/packages/respjson"
+	"github.com/ArkHQ-io/ark-go/webhooks/constant"
+)
+
+// The type of a webhook event.
+type EventType string
+
+const (
+	EventTypeMessageSent           EventType = "MessageSent"
+	EventTypeMessageDelayed        EventType = "MessageDelayed"
+	EventTypeMessageDeliveryFailed EventType = "MessageDeliveryFailed"
+	EventTypeMessageHeld           EventType = "MessageHeld"
+	EventTypeMessageBounced        EventType = "MessageBounced"
+	EventTypeMessageLinkClicked    EventType = "MessageLinkClicked"
+	EventTypeMessageLoaded         EventType = "MessageLoaded"
+	EventTypeDomainDNSError        EventType = "DomainDNSError"
+)
+
+// IsKnown reports whether the event type is one this version of the SDK can
+// decode into a typed variant.
+func (r EventType) IsKnown() bool {
+	switch r {
+	case EventTypeMessageSent, EventTypeMessageDelayed, EventTypeMessageDeliveryFailed, EventTypeMessageHeld,
+		EventTypeMessageBounced, EventTypeMessageLinkClicked, EventTypeMessageLoaded, EventTypeDomainDNSError:
+		return true
+	}
+	return false
+}
+
+func init() {
+	apijson.RegisterUnion[anyEvent](
+		"event",
+		apijson.Discriminator[MessageSentEvent]("MessageSent"),
+		apijson.Discriminator[MessageDelayedEvent]("MessageDelayed"),
+		apijson.Discriminator[MessageDeliveryFailedEvent]("MessageDeliveryFailed"),
+		apijson.Discriminator[MessageHeldEvent]("MessageHeld"),
+		apijson.Discriminator[MessageBouncedEvent]("MessageBounced"),
+		apijson.Discriminator[MessageLinkClickedEvent]("MessageLinkClicked"),
+		apijson.Discriminator[MessageLoadedEvent]("MessageLoaded"),
+		apijson.Discriminator[DomainDNSErrorEvent]("DomainDNSError"),
+	)
+}
+
+// EventUnion contains all possible properties and values from
+// [MessageSentEvent], [MessageDelayedEvent], [MessageDeliveryFailedEvent],
+// [MessageHeldEvent], [MessageBouncedEvent], [MessageLinkClickedEvent],
+// [MessageLoadedEvent], [DomainDNSErrorEvent].
+//
+// Use the [EventUnion.AsAny] method to switch on the variant.
+//
+// Use the methods beginning with 'As' to cast the union to one of its variants.
+type EventUnion struct {
+	// Any of "MessageSent", "MessageDelayed", "MessageDeliveryFailed", "MessageHeld",
+	// "MessageBounced", "MessageLinkClicked", "MessageLoaded", "DomainDNSError".
+	Event EventType `json:"event"`
+	// Unix timestamp of the event
+	Timestamp float64 `json:"timestamp"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	JSON     struct {
+		Event     respjson.Field
+		Timestamp respjson.Field
+		TenantID  respjson.Field
+		raw       string
+	} `json:"-"`
+}
+
+// anyEvent is implemented by each variant of [EventUnion] to add type safety for
+// the return type of [EventUnion.AsAny]
+type anyEvent interface {
+	implEventUnion()
+}
+
+func (MessageSentEvent) implEventUnion()           {}
+func (MessageDelayedEvent) implEventUnion()        {}
+func (MessageDeliveryFailedEvent) implEventUnion() {}
+func (MessageHeldEvent) implEventUnion()           {}
+func (MessageBouncedEvent) implEventUnion()        {}
+func (MessageLinkClickedEvent) implEventUnion()    {}
+func (MessageLoadedEvent) implEventUnion()         {}
+func (DomainDNSErrorEvent) implEventUnion()        {}
+
+// Use the following switch statement to find the correct variant. Unknown event
+// types return nil.
+//
+//	switch variant := EventUnion.AsAny().(type) {
+//	case webhooks.MessageSentEvent:
+//	case webhooks.MessageDelayedEvent:
+//	case webhooks.MessageDeliveryFailedEvent:
+//	case webhooks.MessageHeldEvent:
+//	case webhooks.MessageBouncedEvent:
+//	case webhooks.MessageLinkClickedEvent:
+//	case webhooks.MessageLoadedEvent:
+//	case webhooks.DomainDNSErrorEvent:
+//	default:
+//	  fmt.Errorf("no variant present")
+//	}
+func (u EventUnion) AsAny() anyEvent {
+	if !u.Event.IsKnown() {
+		return nil
+	}
+	var v anyEvent
+	if err := apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v); err != nil {
+		return nil
+	}
+	return v
+}
+
+func (u EventUnion) AsMessageSent() (v MessageSentEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageDelayed() (v MessageDelayedEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageDeliveryFailed() (v MessageDeliveryFailedEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageHeld() (v MessageHeldEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageBounced() (v MessageBouncedEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageLinkClicked() (v MessageLinkClickedEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsMessageLoaded() (v MessageLoadedEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+func (u EventUnion) AsDomainDNSError() (v DomainDNSErrorEvent) {
+	apijson.UnmarshalRoot(json.RawMessage(u.JSON.raw), &v)
+	return
+}
+
+// Returns the unmodified JSON received from the API
+func (u EventUnion) RawJSON() string { return u.JSON.raw }
+
+func (r *EventUnion) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// The email message an event refers to
+type EventMessage struct {
+	// Unique message identifier (token)
+	ID string `json:"id"`
+	// Message direction
+	//
+	// Any of "outgoing", "incoming".
+	Direction string `json:"direction"`
+	// Sender address
+	From string `json:"from"`
+	// SMTP Message-ID header
+	MessageID string `json:"message_id"`
+	// Custom key-value pairs supplied as `metadata` when the email was sent
+	Metadata map[string]string `json:"metadata"`
+	// Spam classification of the message
+	SpamStatus string `json:"spam_status"`
+	// Email subject line
+	Subject string `json:"subject"`
+	// Optional categorization tag
+	Tag string `json:"tag"`
+	// Unix timestamp when the message was created
+	Timestamp float64 `json:"timestamp"`
+	// Recipient address
+	To string `json:"to"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		ID          respjson.Field
+		Direction   respjson.Field
+		From        respjson.Field
+		MessageID   respjson.Field
+		Metadata    respjson.Field
+		SpamStatus  respjson.Field
+		Subject     respjson.Field
+		Tag         respjson.Field
+		Timestamp   respjson.Field
+		To          respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r EventMessage) RawJSON() string { return r.JSON.raw }
+func (r *EventMessage) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// The outcome of a delivery attempt, shared by the MessageSent, MessageDelayed,
+// MessageDeliveryFailed and MessageHeld events.
+type MessageStatusPayload struct {
+	// The message the delivery attempt was made for
+	Message EventMessage `json:"message,required"`
+	// Delivery status, e.g. "Sent", "SoftFail", "HardFail" or "Held"
+	Status string `json:"status,required"`
+	// Human-readable delivery summary
+	Details string `json:"details"`
+	// Raw SMTP response from the receiving mail server
+	Output string `json:"output"`
+	// Whether TLS was used
+	SentWithSsl bool `json:"sent_with_ssl"`
+	// Time taken for the delivery attempt, in seconds
+	Time float64 `json:"time,nullable"`
+	// Unix timestamp of the delivery attempt
+	Timestamp float64 `json:"timestamp"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Message     respjson.Field
+		Status      respjson.Field
+		Details     respjson.Field
+		Output      respjson.Field
+		SentWithSsl respjson.Field
+		Time        respjson.Field
+		Timestamp   respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageStatusPayload) RawJSON() string { return r.JSON.raw }
+func (r *MessageStatusPayload) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Email accepted by the recipient server
+type MessageSentEvent struct {
+	Event constant.MessageSent `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64              `json:"timestamp,required"`
+	Payload   MessageStatusPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageSentEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageSentEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Delivery temporarily failed and will be retried
+type MessageDelayedEvent struct {
+	Event constant.MessageDelayed `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64              `json:"timestamp,required"`
+	Payload   MessageStatusPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageDelayedEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageDelayedEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Delivery permanently failed
+type MessageDeliveryFailedEvent struct {
+	Event constant.MessageDeliveryFailed `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64              `json:"timestamp,required"`
+	Payload   MessageStatusPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageDeliveryFailedEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageDeliveryFailedEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Email held for review
+type MessageHeldEvent struct {
+	Event constant.MessageHeld `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64              `json:"timestamp,required"`
+	Payload   MessageStatusPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageHeldEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageHeldEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Email bounced
+type MessageBouncedEvent struct {
+	Event constant.MessageBounced `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64                    `json:"timestamp,required"`
+	Payload   MessageBouncedEventPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageBouncedEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageBouncedEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+type MessageBouncedEventPayload struct {
+	// The bounce message received from the remote server
+	Bounce EventMessage `json:"bounce,required"`
+	// The message that bounced
+	OriginalMessage EventMessage `json:"original_message,required"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Bounce          respjson.Field
+		OriginalMessage respjson.Field
+		ExtraFields     map[string]respjson.Field
+		raw             string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageBouncedEventPayload) RawJSON() string { return r.JSON.raw }
+func (r *MessageBouncedEventPayload) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Recipient clicked a tracked link
+type MessageLinkClickedEvent struct {
+	Event constant.MessageLinkClicked `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64                        `json:"timestamp,required"`
+	Payload   MessageLinkClickedEventPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageLinkClickedEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageLinkClickedEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+type MessageLinkClickedEventPayload struct {
+	// The message containing the link
+	Message EventMessage `json:"message,required"`
+	// URL that was clicked
+	URL string `json:"url,required"`
+	// IP address of the clicker
+	IPAddress string `json:"ip_address"`
+	// Tracking token of the link
+	Token string `json:"token"`
+	// User agent of the email client
+	UserAgent string `json:"user_agent"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Message     respjson.Field
+		URL         respjson.Field
+		IPAddress   respjson.Field
+		Token       respjson.Field
+		UserAgent   respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageLinkClickedEventPayload) RawJSON() string { return r.JSON.raw }
+func (r *MessageLinkClickedEventPayload) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Recipient opened the email
+type MessageLoadedEvent struct {
+	Event constant.MessageLoaded `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64                   `json:"timestamp,required"`
+	Payload   MessageLoadedEventPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageLoadedEvent) RawJSON() string { return r.JSON.raw }
+func (r *MessageLoadedEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+type MessageLoadedEventPayload struct {
+	// The message that was opened
+	Message EventMessage `json:"message,required"`
+	// IP address of the opener
+	IPAddress string `json:"ip_address"`
+	// User agent of the email client
+	UserAgent string `json:"user_agent"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Message     respjson.Field
+		IPAddress   respjson.Field
+		UserAgent   respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r MessageLoadedEventPayload) RawJSON() string { return r.JSON.raw }
+func (r *MessageLoadedEventPayload) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+// Domain DNS issue detected
+type DomainDNSErrorEvent struct {
+	Event constant.DomainDNSError `json:"event,required"`
+	// Unix timestamp of the event
+	Timestamp float64                    `json:"timestamp,required"`
+	Payload   DomainDNSErrorEventPayload `json:"payload,required"`
+	// The tenant the event belongs to. Only present on platform webhooks.
+	TenantID string `json:"tenant_id"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Event       respjson.Field
+		Timestamp   respjson.Field
+		Payload     respjson.Field
+		TenantID    respjson.Field
+		ExtraFields map[string]respjson.Field
+		raw         string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r DomainDNSErrorEvent) RawJSON() string { return r.JSON.raw }
+func (r *DomainDNSErrorEvent) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
+
+type DomainDNSErrorEventPayload struct {
+	// Domain name
+	Domain string `json:"domain,required"`
+	// Domain ID
+	ID string `json:"id"`
+	// Unix timestamp of the DNS check that failed
+	DNSCheckedAt float64 `json:"dns_checked_at"`
+	// DKIM error message, if any
+	DKIMError string `json:"dkim_error,nullable"`
+	// DKIM record status
+	DKIMStatus string `json:"dkim_status"`
+	// MX error message, if any
+	MxError string `json:"mx_error,nullable"`
+	// MX record status
+	MxStatus string `json:"mx_status"`
+	// Return path error message, if any
+	ReturnPathError string `json:"return_path_error,nullable"`
+	// Return path record status
+	ReturnPathStatus string `json:"return_path_status"`
+	// SPF error message, if any
+	SpfError string `json:"spf_error,nullable"`
+	// SPF record status
+	SpfStatus string `json:"spf_status"`
+	// JSON contains metadata for fields, check presence with [respjson.Field.Valid].
+	JSON struct {
+		Domain           respjson.Field
+		ID               respjson.Field
+		DNSCheckedAt     respjson.Field
+		DKIMError        respjson.Field
+		DKIMStatus       respjson.Field
+		MxError          respjson.Field
+		MxStatus         respjson.Field
+		ReturnPathError  respjson.Field
+		ReturnPathStatus respjson.Field
+		SpfError         respjson.Field
+		SpfStatus        respjson.Field
+		ExtraFields      map[string]respjson.Field
+		raw              string
+	} `json:"-"`
+}
+
+// Returns the unmodified JSON received from the API
+func (r DomainDNSErrorEventPayload) RawJSON() string { return r.JSON.raw }
+func (r *DomainDNSErrorEventPayload) UnmarshalJSON(data []byte) error {
+	return apijson.UnmarshalRoot(data, r)
+}
@@ -0,0 +1,169 @@
+package webhooks_test
+
+import (
+	"testing"
+
+	"github.com/ArkHQ-io/ark-go/webhooks"
+)
+
+const testMessage = `{
+	"id": "aBc123XyZ",
+	"direction": "outgoing",
+	"from": "hello@yourdomain.com",
+	"to": "user@example.com",
+	"subject": "Welcome",
+	"message_id": "<abc@yourdomain.com>",
+	"tag": "welcome",
+	"timestamp": 1700000000.1,
+	"metadata": {"user_id": "usr_123456", "campaign": "onboarding"}
+}`
+
+func TestEventUnionMessageSent(t *testing.T) {
+	var evt webhooks.EventUnion
+	err := evt.UnmarshalJSON([]byte(`{
+		"event": "MessageSent",
+		"timestamp": 1700000001.5,
+		"tenant_id": "tenant_123",
+		"payload": {
+			"message": ` + testMessage + `,
+			"status": "Sent",
+			"details": "Message for user@example.com accepted",
+			"output": "250 OK",
+			"sent_with_ssl": true,
+			"time": 0.25,
+			"timestamp": 1700000001.4
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+
+	if evt.Event != webhooks.EventTypeMessageSent || evt.TenantID != "tenant_123" {
+		t.Fatalf("unexpected event %+v", evt)
+	}
+
+	sent, ok := evt.AsAny().(webhooks.MessageSentEvent)
+	if !ok {
+		t.Fatalf("expected AsAny to return a MessageSentEvent, got %T", evt.AsAny())
+	}
+	if sent.Payload.Status != "Sent" || !sent.Payload.SentWithSsl || sent.Payload.Output != "250 OK" {
+		t.Fatalf("unexpected payload %+v", sent.Payload)
+	}
+	msg := sent.Payload.Message
+	if msg.ID != "aBc123XyZ" || msg.To != "user@example.com" || msg.Metadata["user_id"] != "usr_123456" {
+		t.Fatalf("unexpected message %+v", msg)
+	}
+	if sent.TenantID != "tenant_123" || sent.Timestamp != 1700000001.5 {
+		t.Fatalf("unexpected envelope fields %+v", sent)
+	}
+}
+
+func TestEventUnionMessageBounced(t *testing.T) {
+	var evt webhooks.EventUnion
+	err := evt.UnmarshalJSON([]byte(`{
+		"event": "MessageBounced",
+		"timestamp": 1700000002,
+		"payload": {
+			"original_message": ` + testMessage + `,
+			"bounce": {"id": "bNc456", "from": "mailer-daemon@example.com", "subject": "Undelivered"}
+		}
+	}`))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+
+	switch variant := evt.AsAny().(type) {
+	case webhooks.MessageBouncedEvent:
+		if variant.Payload.OriginalMessage.ID != "aBc123XyZ" || variant.Payload.Bounce.From != "mailer-daemon@example.com" {
+			t.Fatalf("unexpected payload %+v", variant.Payload)
+		}
+		if variant.Payload.OriginalMessage.Metadata["campaign"] != "onboarding" {
+			t.Fatalf("expected metadata to be decoded, got %+v", variant.Payload.OriginalMessage.Metadata)
+		}
+		if variant.JSON.TenantID.Valid() {
+			t.Fatalf("expected tenant_id to be omitted on tenant webhooks")
+		}
+	default:
+		t.Fatalf("expected a MessageBouncedEvent, got %T", variant)
+	}
+}
+
+func TestEventUnionVariants(t *testing.T) {
+	tests := map[string]struct {
+		raw   string
+		check func(t *testing.T, evt webhooks.EventUnion)
+	}{
+		"MessageDelayed": {
+			raw: `{"event":"MessageDelayed","timestamp":1,"payload":{"message":` + testMessage + `,"status":"SoftFail","details":"retrying"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				if v, ok := evt.AsAny().(webhooks.MessageDelayedEvent); !ok || v.Payload.Status != "SoftFail" {
+					t.Fatalf("unexpected variant %#v", evt.AsAny())
+				}
+			},
+		},
+		"MessageDeliveryFailed": {
+			raw: `{"event":"MessageDeliveryFailed","timestamp":1,"payload":{"message":` + testMessage + `,"status":"HardFail","output":"550 5.1.1 no such user"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				if v, ok := evt.AsAny().(webhooks.MessageDeliveryFailedEvent); !ok || v.Payload.Output != "550 5.1.1 no such user" {
+					t.Fatalf("unexpected variant %#v", evt.AsAny())
+				}
+			},
+		},
+		"MessageHeld": {
+			raw: `{"event":"MessageHeld","timestamp":1,"payload":{"message":` + testMessage + `,"status":"Held"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				if v, ok := evt.AsAny().(webhooks.MessageHeldEvent); !ok || v.Payload.Status != "Held" {
+					t.Fatalf("unexpected variant %#v", evt.AsAny())
+				}
+			},
+		},
+		"MessageLinkClicked": {
+			raw: `{"event":"MessageLinkClicked","timestamp":1,"payload":{"message":` + testMessage + `,"url":"https://example.com","ip_address":"127.0.0.1"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				v := evt.AsMessageLinkClicked()
+				if v.Payload.URL != "https://example.com" || v.Payload.IPAddress != "127.0.0.1" {
+					t.Fatalf("unexpected payload %+v", v.Payload)
+				}
+			},
+		},
+		"MessageLoaded": {
+			raw: `{"event":"MessageLoaded","timestamp":1,"payload":{"message":` + testMessage + `,"user_agent":"Mozilla/5.0"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				v := evt.AsMessageLoaded()
+				if v.Payload.UserAgent != "Mozilla/5.0" || v.Payload.Message.Subject != "Welcome" {
+					t.Fatalf("unexpected payload %+v", v.Payload)
+				}
+			},
+		},
+		"DomainDNSError": {
+			raw: `{"event":"DomainDNSError","timestamp":1,"tenant_id":"tenant_123","payload":{"domain":"yourdomain.com","spf_status":"Invalid","spf_error":"No SPF record"}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				v, ok := evt.AsAny().(webhooks.DomainDNSErrorEvent)
+				if !ok || v.Payload.Domain != "yourdomain.com" || v.Payload.SpfError != "No SPF record" {
+					t.Fatalf("unexpected variant %#v", evt.AsAny())
+				}
+			},
+		},
+		"unknown": {
+			raw: `{"event":"SomethingNew","timestamp":1,"payload":{}}`,
+			check: func(t *testing.T, evt webhooks.EventUnion) {
+				if evt.Event != "SomethingNew" {
+					t.Fatalf("expected the raw event type to be kept, got %q", evt.Event)
+				}
+				if v := evt.AsAny(); v != nil {
+					t.Fatalf("expected unknown events to have no variant, got %T", v)
+				}
+			},
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			var evt webhooks.EventUnion
+			if err := evt.UnmarshalJSON([]byte(test.raw)); err != nil {
+				t.Fatalf("err should be nil: %s", err)
+			}
+			test.check(t, evt)
+		})
+	}
+}
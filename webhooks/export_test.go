@@ -0,0 +1,6 @@
+package webhooks
+
+// On registers a handler decoding events into any type, for tests.
+func On[T any](r *Router, typ EventType, fn HandlerFunc[T]) {
+	on(r, typ, fn)
+}
@@ -0,0 +1,73 @@
+package webhooks
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+)
+
+// MaxPayloadBytes bounds how much of a request body [Middleware] will read
+// before giving up on a delivery.
+const MaxPayloadBytes = 1 << 20
+
+// Middleware returns an [http.Handler] middleware which rejects any request
+// that does not carry a valid signature for secret. Verified requests are passed
+// to the next handler with the body intact.
+//
+// Requests with missing or malformed headers are answered with 400 Bad Request,
+// and requests with a bad signature or a stale timestamp with 401 Unauthorized.
+func Middleware(secret string, opts ...Option) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, ok := verifyRequest(w, r, secret, opts)
+			if !ok {
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(payload))
+			r.ContentLength = int64(len(payload))
+			r.GetBody = func() (io.ReadCloser, error) { return io.NopCloser(bytes.NewReader(payload)), nil }
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// verifyRequest reads and verifies the body of r. When verification fails, the
+// error response has already been written to w and ok is false.
+func verifyRequest(w http.ResponseWriter, r *http.Request, secret string, opts []Option) (payload []byte, ok bool) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return nil, false
+	}
+
+	payload, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxPayloadBytes))
+	r.Body.Close()
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			http.Error(w, http.StatusText(http.StatusRequestEntityTooLarge), http.StatusRequestEntityTooLarge)
+			return nil, false
+		}
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return nil, false
+	}
+
+	if err := Verify(payload, r.Header, secret, opts...); err != nil {
+		http.Error(w, err.Error(), verifyStatus(err))
+		return nil, false
+	}
+	return payload, true
+}
+
+func verifyStatus(err error) int {
+	switch {
+	case errors.Is(err, ErrMissingHeaders), errors.Is(err, ErrInvalidTimestamp):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrInvalidSecret):
+		return http.StatusInternalServerError
+	default:
+		return http.StatusUnauthorized
+	}
+}
@@ -0,0 +1,86 @@
+package webhooks_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/webhooks"
+)
+
+func TestMiddleware(t *testing.T) {
+	var received string
+	handler := webhooks.Middleware(testSecret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	tests := map[string]struct {
+		method  string
+		body    string
+		headers http.Header
+		status  int
+	}{
+		"valid": {
+			method:  http.MethodPost,
+			body:    testPayload,
+			headers: signedHeaders(t, testPayload, time.Now()),
+			status:  http.StatusNoContent,
+		},
+		"forged": {
+			method:  http.MethodPost,
+			body:    `{"event":"MessageBounced"}`,
+			headers: signedHeaders(t, testPayload, time.Now()),
+			status:  http.StatusUnauthorized,
+		},
+		"stale": {
+			method:  http.MethodPost,
+			body:    testPayload,
+			headers: signedHeaders(t, testPayload, time.Now().Add(-time.Hour)),
+			status:  http.StatusUnauthorized,
+		},
+		"unsigned": {
+			method:  http.MethodPost,
+			body:    testPayload,
+			headers: http.Header{},
+			status:  http.StatusBadRequest,
+		},
+		"wrong method": {
+			method:  http.MethodGet,
+			headers: http.Header{},
+			status:  http.StatusMethodNotAllowed,
+		},
+		"too large": {
+			method:  http.MethodPost,
+			body:    strings.Repeat("a", webhooks.MaxPayloadBytes+1),
+			headers: http.Header{},
+			status:  http.StatusRequestEntityTooLarge,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			received = ""
+			req := httptest.NewRequest(test.method, "/webhooks/ark", strings.NewReader(test.body))
+			for k, v := range test.headers {
+				req.Header[k] = v
+			}
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != test.status {
+				t.Fatalf("expected status %d, got %d: %s", test.status, rec.Code, rec.Body.String())
+			}
+			if test.status == http.StatusNoContent && received != test.body {
+				t.Fatalf("expected the handler to receive the original body, got %q", received)
+			}
+			if test.status != http.StatusNoContent && received != "" {
+				t.Fatalf("expected the handler not to be called")
+			}
+		})
+	}
+}
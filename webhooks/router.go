@@ -0,0 +1,194 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+	"sync"
+
+	"github.com/ArkHQ-io/ark-go/internal/apijson"
+)
+
+// HandlerFunc handles a single webhook event.
+type HandlerFunc[T any] func(ctx context.Context, evt T) error
+
+// Router verifies webhook deliveries and dispatches them to the handler
+// registered for their event type. It implements [http.Handler], so it can be
+// mounted directly on a [http.ServeMux].
+//
+// The status code written by the router tells Ark whether a delivery should be
+// retried:
+//
+//   - 204 No Content when the handler succeeds, or when no handler is
+//     registered for the event type. Ark will not retry these deliveries.
+//   - 500 Internal Server Error when the handler returns an error or panics.
+//     Ark will retry these with exponential backoff, see
+//     [ark.TenantWebhookService.ListDeliveries].
+//   - 4xx when the delivery fails verification or cannot be decoded, including
+//     into the event type of its handler. Ark will not retry these deliveries.
+//
+// [ark.TenantWebhookService.ListDeliveries]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#TenantWebhookService.ListDeliveries
+type Router struct {
+	secret   string
+	opts     []Option
+	handlers map[EventType]HandlerFunc[EventUnion]
+	fallback HandlerFunc[EventUnion]
+	onError  func(r *http.Request, evt *EventUnion, err error)
+	mu       sync.RWMutex
+}
+
+// NewRouter returns a [Router] which verifies deliveries with the given signing
+// secret and options.
+func NewRouter(secret string, opts ...Option) *Router {
+	return &Router{
+		secret:   secret,
+		opts:     opts,
+		handlers: map[EventType]HandlerFunc[EventUnion]{},
+	}
+}
+
+// PanicError is passed to the [Router.OnError] callback when a handler panics.
+type PanicError struct {
+	Value any
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("webhooks: handler panicked: %v", e.Value)
+}
+
+// decodeError is returned by the handlers registered with on when the event
+// cannot be decoded into their type. Retrying such deliveries cannot succeed.
+type decodeError struct {
+	err error
+}
+
+func (e *decodeError) Error() string { return e.err.Error() }
+func (e *decodeError) Unwrap() error { return e.err }
+
+func on[T any](r *Router, typ EventType, fn HandlerFunc[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.handlers[typ] = func(ctx context.Context, evt EventUnion) error {
+		var v T
+		if err := apijson.UnmarshalRoot(json.RawMessage(evt.RawJSON()), &v); err != nil {
+			return &decodeError{fmt.Errorf("webhooks: error parsing %s event: %w", typ, err)}
+		}
+		return fn(ctx, v)
+	}
+}
+
+// OnMessageSent registers the handler of MessageSent events.
+func (r *Router) OnMessageSent(fn HandlerFunc[MessageSentEvent]) {
+	on(r, EventTypeMessageSent, fn)
+}
+
+// OnMessageDelayed registers the handler of MessageDelayed events.
+func (r *Router) OnMessageDelayed(fn HandlerFunc[MessageDelayedEvent]) {
+	on(r, EventTypeMessageDelayed, fn)
+}
+
+// OnMessageDeliveryFailed registers the handler of MessageDeliveryFailed events.
+func (r *Router) OnMessageDeliveryFailed(fn HandlerFunc[MessageDeliveryFailedEvent]) {
+	on(r, EventTypeMessageDeliveryFailed, fn)
+}
+
+// OnMessageHeld registers the handler of MessageHeld events.
+func (r *Router) OnMessageHeld(fn HandlerFunc[MessageHeldEvent]) {
+	on(r, EventTypeMessageHeld, fn)
+}
+
+// OnMessageBounced registers the handler of MessageBounced events.
+func (r *Router) OnMessageBounced(fn HandlerFunc[MessageBouncedEvent]) {
+	on(r, EventTypeMessageBounced, fn)
+}
+
+// OnMessageLinkClicked registers the handler of MessageLinkClicked events.
+func (r *Router) OnMessageLinkClicked(fn HandlerFunc[MessageLinkClickedEvent]) {
+	on(r, EventTypeMessageLinkClicked, fn)
+}
+
+// OnMessageLoaded registers the handler of MessageLoaded events.
+func (r *Router) OnMessageLoaded(fn HandlerFunc[MessageLoadedEvent]) {
+	on(r, EventTypeMessageLoaded, fn)
+}
+
+// OnDomainDNSError registers the handler of DomainDNSError events.
+func (r *Router) OnDomainDNSError(fn HandlerFunc[DomainDNSErrorEvent]) {
+	on(r, EventTypeDomainDNSError, fn)
+}
+
+// Fallback registers a handler for every event without a dedicated handler,
+// including event types this version of the SDK doesn't know about.
+func (r *Router) Fallback(fn HandlerFunc[EventUnion]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.fallback = fn
+}
+
+// OnError registers a callback which is invoked whenever a handler fails or
+// panics, e.g. for logging. evt is nil if the delivery could not be decoded.
+func (r *Router) OnError(fn func(req *http.Request, evt *EventUnion, err error)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.onError = fn
+}
+
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	payload, ok := verifyRequest(w, req, r.secret, r.opts)
+	if !ok {
+		return
+	}
+
+	evt, err := parse(payload)
+	if err != nil {
+		r.reportError(req, nil, err)
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+
+	r.mu.RLock()
+	handler, ok := r.handlers[evt.Event]
+	if !ok {
+		handler = r.fallback
+	}
+	r.mu.RUnlock()
+
+	if handler == nil {
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if err := r.dispatch(req.Context(), handler, *evt); err != nil {
+		r.reportError(req, evt, err)
+		status := http.StatusInternalServerError
+		var decodeErr *decodeError
+		if errors.As(err, &decodeErr) {
+			status = http.StatusBadRequest
+		}
+		http.Error(w, http.StatusText(status), status)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r *Router) dispatch(ctx context.Context, handler HandlerFunc[EventUnion], evt EventUnion) (err error) {
+	defer func() {
+		if v := recover(); v != nil {
+			err = &PanicError{Value: v, Stack: debug.Stack()}
+		}
+	}()
+	return handler(ctx, evt)
+}
+
+func (r *Router) reportError(req *http.Request, evt *EventUnion, err error) {
+	r.mu.RLock()
+	onError := r.onError
+	r.mu.RUnlock()
+	if onError != nil {
+		onError(req, evt, err)
+	}
+}
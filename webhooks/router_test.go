@@ -0,0 +1,171 @@
+package webhooks_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/webhooks"
+)
+
+func deliver(t *testing.T, h http.Handler, payload string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/ark", strings.NewReader(payload))
+	for k, v := range signedHeaders(t, payload, time.Now()) {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouterDispatch(t *testing.T) {
+	router := webhooks.NewRouter(testSecret)
+
+	var bounced webhooks.MessageBouncedEvent
+	router.OnMessageBounced(func(ctx context.Context, evt webhooks.MessageBouncedEvent) error {
+		if ctx == nil {
+			t.Fatal("expected a request context")
+		}
+		bounced = evt
+		return nil
+	})
+	router.OnMessageSent(func(ctx context.Context, evt webhooks.MessageSentEvent) error {
+		t.Fatal("MessageSent handler should not be called")
+		return nil
+	})
+
+	rec := deliver(t, router, `{"event":"MessageBounced","timestamp":1,"payload":{"original_message":{"id":"aBc123XyZ"},"bounce":{"id":"bNc456"}}}`)
+	if rec.Code != http.StatusNoContent {
+		t.Fatalf("expected status %d, got %d", http.StatusNoContent, rec.Code)
+	}
+	if bounced.Payload.OriginalMessage.ID != "aBc123XyZ" {
+		t.Fatalf("expected handler to receive the decoded event, got %+v", bounced)
+	}
+}
+
+func TestRouterStatusCodes(t *testing.T) {
+	tests := map[string]struct {
+		setup   func(r *webhooks.Router)
+		payload string
+		status  int
+		failed  bool
+	}{
+		"no handler": {
+			setup:   func(r *webhooks.Router) {},
+			payload: `{"event":"MessageLoaded","timestamp":1,"payload":{}}`,
+			status:  http.StatusNoContent,
+		},
+		"handler error": {
+			setup: func(r *webhooks.Router) {
+				r.OnMessageHeld(func(ctx context.Context, evt webhooks.MessageHeldEvent) error {
+					return errors.New("database unavailable")
+				})
+			},
+			payload: `{"event":"MessageHeld","timestamp":1,"payload":{"status":"Held"}}`,
+			status:  http.StatusInternalServerError,
+			failed:  true,
+		},
+		"handler panic": {
+			setup: func(r *webhooks.Router) {
+				r.OnDomainDNSError(func(ctx context.Context, evt webhooks.DomainDNSErrorEvent) error {
+					panic("boom")
+				})
+			},
+			payload: `{"event":"DomainDNSError","timestamp":1,"payload":{"domain":"yourdomain.com"}}`,
+			status:  http.StatusInternalServerError,
+			failed:  true,
+		},
+		"fallback": {
+			setup: func(r *webhooks.Router) {
+				r.Fallback(func(ctx context.Context, evt webhooks.EventUnion) error {
+					if evt.Event != "SomethingNew" {
+						return errors.New("unexpected event")
+					}
+					return nil
+				})
+			},
+			payload: `{"event":"SomethingNew","timestamp":1,"payload":{}}`,
+			status:  http.StatusNoContent,
+		},
+		"undecodable event": {
+			setup: func(r *webhooks.Router) {
+				// An object cannot be decoded into a slice.
+				webhooks.On(r, webhooks.EventTypeMessageHeld, func(ctx context.Context, evt []string) error {
+					return nil
+				})
+			},
+			payload: `{"event":"MessageHeld","timestamp":1,"payload":{"status":"Held"}}`,
+			status:  http.StatusBadRequest,
+			failed:  true,
+		},
+		"malformed": {
+			setup:   func(r *webhooks.Router) {},
+			payload: `not json`,
+			status:  http.StatusBadRequest,
+			failed:  true,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			router := webhooks.NewRouter(testSecret)
+			var reported error
+			router.OnError(func(req *http.Request, evt *webhooks.EventUnion, err error) {
+				reported = err
+			})
+			test.setup(router)
+
+			rec := deliver(t, router, test.payload)
+			if rec.Code != test.status {
+				t.Fatalf("expected status %d, got %d", test.status, rec.Code)
+			}
+			if test.failed != (reported != nil) {
+				t.Fatalf("expected error reported to be %v, got %v", test.failed, reported)
+			}
+		})
+	}
+}
+
+func TestRouterPanicError(t *testing.T) {
+	router := webhooks.NewRouter(testSecret)
+	router.OnMessageSent(func(ctx context.Context, evt webhooks.MessageSentEvent) error {
+		panic("boom")
+	})
+	var panicErr *webhooks.PanicError
+	router.OnError(func(req *http.Request, evt *webhooks.EventUnion, err error) {
+		errors.As(err, &panicErr)
+	})
+
+	deliver(t, router, testPayload)
+	if panicErr == nil || panicErr.Value != "boom" || len(panicErr.Stack) == 0 {
+		t.Fatalf("expected a PanicError carrying the panic value, got %+v", panicErr)
+	}
+}
+
+func TestRouterRejectsForgery(t *testing.T) {
+	called := false
+	router := webhooks.NewRouter(testSecret)
+	router.Fallback(func(ctx context.Context, evt webhooks.EventUnion) error {
+		called = true
+		return nil
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/ark", strings.NewReader(testPayload))
+	for k, v := range signedHeaders(t, `{"event":"other"}`, time.Now()) {
+		req.Header[k] = v
+	}
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status %d, got %d", http.StatusUnauthorized, rec.Code)
+	}
+	if called {
+		t.Fatal("handler should not be called for forged deliveries")
+	}
+}
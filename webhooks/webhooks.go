@@ -0,0 +1,210 @@
+// Package webhooks verifies and decodes the event deliveries that Ark POSTs to
+// endpoints registered with [ark.TenantWebhookService.New] or
+// [ark.PlatformWebhookService.New].
+//
+// Every delivery carries three headers: a unique message ID, the Unix time at
+// which it was signed, and one or more space-delimited signatures of the form
+// "v1,<base64>". A signature is the HMAC-SHA256 of "{id}.{timestamp}.{body}"
+// keyed with the webhook's signing secret.
+//
+// [ark.TenantWebhookService.New]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#TenantWebhookService.New
+// [ark.PlatformWebhookService.New]: https://pkg.go.dev/github.com/ArkHQ-io/ark-go#PlatformWebhookService.New
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The headers sent with every webhook delivery.
+const (
+	HeaderID        = "Webhook-Id"
+	HeaderTimestamp = "Webhook-Timestamp"
+	HeaderSignature = "Webhook-Signature"
+)
+
+// DefaultTolerance is the maximum difference allowed between the signed
+// timestamp of a delivery and the local clock. Deliveries outside of this window
+// are rejected to protect against replay attacks.
+const DefaultTolerance = 5 * time.Minute
+
+const secretPrefix = "whsec_"
+const signatureVersion = "v1"
+
+var (
+	// ErrMissingHeaders is returned when a delivery lacks one of the webhook
+	// headers.
+	ErrMissingHeaders = errors.New("webhooks: missing required webhook headers")
+	// ErrInvalidTimestamp is returned when the timestamp header cannot be parsed.
+	ErrInvalidTimestamp = errors.New("webhooks: invalid webhook timestamp")
+	// ErrTimestampTooOld is returned when a delivery was signed longer ago than
+	// the configured tolerance.
+	ErrTimestampTooOld = errors.New("webhooks: webhook timestamp is too old")
+	// ErrTimestampTooNew is returned when a delivery is signed further in the
+	// future than the configured tolerance.
+	ErrTimestampTooNew = errors.New("webhooks: webhook timestamp is too new")
+	// ErrNoMatchingSignature is returned when none of the signatures in the
+	// delivery match the payload.
+	ErrNoMatchingSignature = errors.New("webhooks: no matching signature found")
+	// ErrInvalidSecret is returned when the signing secret is empty or malformed.
+	ErrInvalidSecret = errors.New("webhooks: invalid signing secret")
+)
+
+// Option configures how deliveries are verified.
+type Option func(*config)
+
+type config struct {
+	tolerance time.Duration
+	now       func() time.Time
+}
+
+func newConfig(opts []Option) config {
+	cfg := config{tolerance: DefaultTolerance, now: time.Now}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return cfg
+}
+
+// WithTolerance overrides [DefaultTolerance]. A tolerance of zero or less
+// disables the timestamp check entirely, which should only be done in tests.
+func WithTolerance(d time.Duration) Option {
+	return func(c *config) { c.tolerance = d }
+}
+
+// WithClock replaces the clock used to evaluate the timestamp tolerance.
+func WithClock(now func() time.Time) Option {
+	return func(c *config) { c.now = now }
+}
+
+// Verify checks that payload was signed by Ark with the given secret and that
+// the signature is recent enough. The payload must be the exact bytes of the
+// request body, before any JSON decoding. A secret with the "whsec_" prefix is
+// base64-decoded, and any other secret is used as-is.
+func Verify(payload []byte, headers http.Header, secret string, opts ...Option) error {
+	cfg := newConfig(opts)
+
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return err
+	}
+
+	id := headers.Get(HeaderID)
+	ts := headers.Get(HeaderTimestamp)
+	sigs := headers.Get(HeaderSignature)
+	if id == "" || ts == "" || sigs == "" {
+		return ErrMissingHeaders
+	}
+
+	sec, err := strconv.ParseInt(ts, 10, 64)
+	if err != nil {
+		return ErrInvalidTimestamp
+	}
+	if cfg.tolerance > 0 {
+		now := cfg.now()
+		signedAt := time.Unix(sec, 0)
+		if now.Sub(signedAt) > cfg.tolerance {
+			return ErrTimestampTooOld
+		}
+		if signedAt.Sub(now) > cfg.tolerance {
+			return ErrTimestampTooNew
+		}
+	}
+
+	expected := sign(key, id, ts, payload)
+	for _, sig := range strings.Fields(sigs) {
+		version, value, ok := strings.Cut(sig, ",")
+		if !ok || version != signatureVersion {
+			continue
+		}
+		got, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			continue
+		}
+		if hmac.Equal(got, expected) {
+			return nil
+		}
+	}
+	return ErrNoMatchingSignature
+}
+
+// Sign produces the signature header value Ark would send for payload. It is
+// intended for building fixtures in tests.
+func Sign(payload []byte, id string, timestamp time.Time, secret string) (string, error) {
+	key, err := decodeSecret(secret)
+	if err != nil {
+		return "", err
+	}
+	sig := sign(key, id, strconv.FormatInt(timestamp.Unix(), 10), payload)
+	return signatureVersion + "," + base64.StdEncoding.EncodeToString(sig), nil
+}
+
+// SignedHeaders returns the full set of webhook headers for payload, as Ark
+// would send them. It is intended for building fixtures in tests.
+func SignedHeaders(payload []byte, id string, timestamp time.Time, secret string) (http.Header, error) {
+	sig, err := Sign(payload, id, timestamp, secret)
+	if err != nil {
+		return nil, err
+	}
+	h := http.Header{}
+	h.Set(HeaderID, id)
+	h.Set(HeaderTimestamp, strconv.FormatInt(timestamp.Unix(), 10))
+	h.Set(HeaderSignature, sig)
+	return h, nil
+}
+
+// Unwrap verifies the delivery like [Verify] and then decodes its body into an
+// [EventUnion].
+func Unwrap(payload []byte, headers http.Header, secret string, opts ...Option) (*EventUnion, error) {
+	if err := Verify(payload, headers, secret, opts...); err != nil {
+		return nil, err
+	}
+	return parse(payload)
+}
+
+func parse(payload []byte) (*EventUnion, error) {
+	if !json.Valid(payload) {
+		return nil, errors.New("webhooks: webhook payload is not valid JSON")
+	}
+	res := &EventUnion{}
+	if err := res.UnmarshalJSON(payload); err != nil {
+		return nil, fmt.Errorf("webhooks: error parsing webhook payload: %w", err)
+	}
+	return res, nil
+}
+
+// decodeSecret returns the HMAC key of a secret. Secrets with the "whsec_"
+// prefix are base64 after it, while other secrets are used as-is, even if they
+// happen to be valid base64.
+func decodeSecret(secret string) ([]byte, error) {
+	encoded, ok := strings.CutPrefix(secret, secretPrefix)
+	if !ok {
+		if secret == "" {
+			return nil, ErrInvalidSecret
+		}
+		return []byte(secret), nil
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return key, nil
+}
+
+func sign(key []byte, id string, ts string, payload []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(id))
+	mac.Write([]byte{'.'})
+	mac.Write([]byte(ts))
+	mac.Write([]byte{'.'})
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,167 @@
+package webhooks_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/ArkHQ-io/ark-go/webhooks"
+)
+
+var testSecret = "whsec_" + base64.StdEncoding.EncodeToString([]byte("super-secret-signing-key"))
+
+const testPayload = `{"event":"MessageSent","timestamp":1700000000.5,"tenant_id":"tenant_123","payload":{"status":"Sent","message":{"id":"aBc123XyZ"}}}`
+
+func signedHeaders(t *testing.T, payload string, at time.Time) http.Header {
+	t.Helper()
+	h, err := webhooks.SignedHeaders([]byte(payload), "msg_2abc", at, testSecret)
+	if err != nil {
+		t.Fatalf("failed to sign payload: %s", err)
+	}
+	return h
+}
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	clock := webhooks.WithClock(func() time.Time { return now })
+
+	tests := map[string]struct {
+		headers func() http.Header
+		payload string
+		secret  string
+		err     error
+	}{
+		"valid": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: testPayload,
+			secret:  testSecret,
+		},
+		"raw secret": {
+			headers: func() http.Header {
+				h, err := webhooks.SignedHeaders([]byte(testPayload), "msg_2abc", now, "c2VjcmV0")
+				if err != nil {
+					t.Fatalf("failed to sign payload: %s", err)
+				}
+				return h
+			},
+			payload: testPayload,
+			secret:  "c2VjcmV0",
+		},
+		"base64 secret without prefix": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: testPayload,
+			secret:  testSecret[len("whsec_"):],
+			err:     webhooks.ErrNoMatchingSignature,
+		},
+		"malformed secret": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: testPayload,
+			secret:  "whsec_not base64!",
+			err:     webhooks.ErrInvalidSecret,
+		},
+		"multiple signatures": {
+			headers: func() http.Header {
+				h := signedHeaders(t, testPayload, now)
+				h.Set(webhooks.HeaderSignature, "v1,bm90LXRoZS1zaWduYXR1cmU= v2,abc "+h.Get(webhooks.HeaderSignature))
+				return h
+			},
+			payload: testPayload,
+			secret:  testSecret,
+		},
+		"tampered payload": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: `{"event":"MessageBounced"}`,
+			secret:  testSecret,
+			err:     webhooks.ErrNoMatchingSignature,
+		},
+		"wrong secret": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: testPayload,
+			secret:  "whsec_" + base64.StdEncoding.EncodeToString([]byte("another-key")),
+			err:     webhooks.ErrNoMatchingSignature,
+		},
+		"missing headers": {
+			headers: func() http.Header {
+				h := signedHeaders(t, testPayload, now)
+				h.Del(webhooks.HeaderID)
+				return h
+			},
+			payload: testPayload,
+			secret:  testSecret,
+			err:     webhooks.ErrMissingHeaders,
+		},
+		"invalid timestamp": {
+			headers: func() http.Header {
+				h := signedHeaders(t, testPayload, now)
+				h.Set(webhooks.HeaderTimestamp, "yesterday")
+				return h
+			},
+			payload: testPayload,
+			secret:  testSecret,
+			err:     webhooks.ErrInvalidTimestamp,
+		},
+		"replayed": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now.Add(-6*time.Minute)) },
+			payload: testPayload,
+			secret:  testSecret,
+			err:     webhooks.ErrTimestampTooOld,
+		},
+		"from the future": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now.Add(6*time.Minute)) },
+			payload: testPayload,
+			secret:  testSecret,
+			err:     webhooks.ErrTimestampTooNew,
+		},
+		"empty secret": {
+			headers: func() http.Header { return signedHeaders(t, testPayload, now) },
+			payload: testPayload,
+			secret:  "whsec_",
+			err:     webhooks.ErrInvalidSecret,
+		},
+	}
+
+	for name, test := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := webhooks.Verify([]byte(test.payload), test.headers(), test.secret, clock)
+			if !errors.Is(err, test.err) {
+				t.Fatalf("expected error %v, got %v", test.err, err)
+			}
+		})
+	}
+}
+
+func TestVerifyTolerance(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	headers := signedHeaders(t, testPayload, now.Add(-time.Hour))
+
+	err := webhooks.Verify([]byte(testPayload), headers, testSecret,
+		webhooks.WithClock(func() time.Time { return now }),
+		webhooks.WithTolerance(2*time.Hour),
+	)
+	if err != nil {
+		t.Fatalf("expected a wider tolerance to accept the delivery, got %v", err)
+	}
+}
+
+func TestUnwrap(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	headers := signedHeaders(t, testPayload, now)
+
+	evt, err := webhooks.Unwrap([]byte(testPayload), headers, testSecret, webhooks.WithClock(func() time.Time { return now }))
+	if err != nil {
+		t.Fatalf("err should be nil: %s", err)
+	}
+	if evt.Event != webhooks.EventTypeMessageSent || evt.TenantID != "tenant_123" || evt.Timestamp != 1700000000.5 {
+		t.Fatalf("unexpected event %+v", evt)
+	}
+	if sent := evt.AsMessageSent(); sent.Payload.Status != "Sent" || sent.Payload.Message.ID != "aBc123XyZ" {
+		t.Fatalf("unexpected payload %+v", sent.Payload)
+	}
+
+	_, err = webhooks.Unwrap([]byte(testPayload), headers, testSecret)
+	if !errors.Is(err, webhooks.ErrTimestampTooOld) {
+		t.Fatalf("expected stale delivery to be rejected, got %v", err)
+	}
+}